@@ -0,0 +1,88 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package capability
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/zosmac/gocore"
+)
+
+type (
+	// Mode indicates the extent to which a capability is available.
+	Mode string
+
+	// Capability reports the availability of a subsystem and the measurements and observations it affects.
+	Capability struct {
+		Name         string   `json:"name" gomon:"property"`
+		Mode         Mode     `json:"mode" gomon:"property"`
+		Detail       string   `json:"detail,omitempty" gomon:"property"`
+		Measurements []string `json:"measurements,omitempty" gomon:"property"` // disabled if capability disabled
+		Observations []string `json:"observations,omitempty" gomon:"property"` // disabled if capability disabled
+	}
+)
+
+const (
+	// capability modes.
+	Full     Mode = "full"
+	Degraded Mode = "degraded"
+	Disabled Mode = "disabled"
+)
+
+var (
+	// capabilities records the results of the probes.
+	capabilities []Capability
+	capLock      sync.RWMutex
+)
+
+// Record adds the results of probes to the capability report, replacing those of an earlier probe.
+func Record(cs ...Capability) {
+	capLock.Lock()
+	defer capLock.Unlock()
+	for _, c := range cs {
+		capabilities = slices.DeleteFunc(capabilities, func(cc Capability) bool {
+			return cc.Name == c.Name
+		})
+	}
+	capabilities = append(capabilities, cs...)
+	slices.SortFunc(capabilities, func(a, b Capability) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Report returns the capability report.
+func Report() []Capability {
+	capLock.RLock()
+	defer capLock.RUnlock()
+	return slices.Clone(capabilities)
+}
+
+// Summary formats the capability report for logging.
+func Summary() string {
+	var ss []string
+	for _, c := range Report() {
+		s := c.Name + "=" + string(c.Mode)
+		if c.Detail != "" {
+			s += " (" + c.Detail + ")"
+		}
+		ss = append(ss, s)
+	}
+	return strings.Join(ss, ", ")
+}
+
+// Degrade removes the measurements and observations of disabled capabilities from the options selected.
+func Degrade(measurements, observations *gocore.Options) {
+	for _, c := range Report() {
+		if c.Mode != Disabled {
+			continue
+		}
+		measurements.Selected = slices.DeleteFunc(measurements.Selected, func(s string) bool {
+			return slices.Contains(c.Measurements, s)
+		})
+		observations.Selected = slices.DeleteFunc(observations.Selected, func(s string) bool {
+			return slices.Contains(c.Observations, s)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package capability records which of the "gomon" command's subsystems are available to it, so that
+gomon may run without root authority, degrading or disabling the measurements and observations
+that require privileges it lacks.
+
+At startup the packages that implement the measurements and observations probe their prerequisites,
+such as the netlink process connector, taskstats, the process I/O accounting of other users, inotify
+limits, and lsof. The results are reported in the "start" log and in the server measurement.
+
+The capability package defines the following command line flag:
+* -runas: the name of a user to which gomon switches after opening its privileged resources
+*/
+package capability
@@ -0,0 +1,46 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+//go:build !windows
+
+package capability
+
+import (
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/zosmac/gocore"
+)
+
+// Drop switches gomon to the user specified by the runas flag.
+func Drop() error {
+	if flags.runas == "" {
+		return nil
+	}
+	u, err := user.Lookup(flags.runas)
+	if err != nil {
+		return gocore.Error("Lookup", err, map[string]string{
+			"user": flags.runas,
+		})
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(u.Gid)
+
+	if err := syscall.Setgroups([]int{gid}); err != nil {
+		return gocore.Error("setgroups", err)
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return gocore.Error("setgid", err)
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return gocore.Error("setuid", err)
+	}
+
+	gocore.Error("runas", nil, map[string]string{
+		"user": flags.runas,
+		"uid":  u.Uid,
+		"gid":  u.Gid,
+	}).Info()
+
+	return nil
+}
@@ -0,0 +1,15 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package capability
+
+import (
+	"github.com/zosmac/gocore"
+)
+
+// Drop switches gomon to the user specified by the runas flag. Unsupported on windows.
+func Drop() error {
+	if flags.runas == "" {
+		return nil
+	}
+	return gocore.Unsupported()
+}
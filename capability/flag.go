@@ -0,0 +1,24 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package capability
+
+import (
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		runas string
+	}{}
+)
+
+// init initializes the command line flags.
+func init() {
+	gocore.Flags.Var(
+		&flags.runas,
+		"runas",
+		"[-runas <user>]",
+		"The `user` to which to switch after opening privileged resources, reducing the measurements of other users' processes",
+	)
+}
@@ -0,0 +1,166 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+const (
+	// chrony command protocol (see chrony's candm.h).
+	chronyAddress      = "127.0.0.1:323"
+	chronyVersion      = 6
+	chronyRequest      = 1
+	chronyReply        = 2
+	chronyReqTracking  = 33
+	chronyRpyTracking  = 5
+	chronySuccess      = 0
+	chronyHeaderSize   = 28 // of a reply
+	chronyTrackingSize = 80
+)
+
+var (
+	// chronyLeapStatus maps chrony's leap status values.
+	chronyLeapStatus = []string{"normal", "insert second", "delete second", "unsynchronized"}
+)
+
+// tracking queries the chrony daemon for its tracking report as chronyc does, through the daemon's unix
+// domain socket if gomon may bind its own socket beside it, or else from localhost over UDP.
+func tracking() (Tracking, error) {
+	conn, err := chronyDial()
+	if err != nil {
+		return Tracking{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	// the request is padded to the length of the reply to deter traffic amplification
+	req := make([]byte, chronyHeaderSize+chronyTrackingSize)
+	req[0] = chronyVersion
+	req[1] = chronyRequest
+	binary.BigEndian.PutUint16(req[4:], chronyReqTracking)
+	seq := uint32(time.Now().UnixNano())
+	binary.BigEndian.PutUint32(req[8:], seq)
+	if _, err := conn.Write(req); err != nil {
+		return Tracking{}, gocore.Error("Write", err)
+	}
+
+	rpy := make([]byte, 512)
+	n, err := conn.Read(rpy)
+	if err != nil {
+		return Tracking{}, gocore.Error("Read", err)
+	}
+	return parseTracking(rpy[:n], seq)
+}
+
+// chronyDial connects to the chrony daemon's command socket. A unix domain datagram socket must be bound
+// for the reply, which chronyc binds in the daemon's socket directory, writable only by root and chrony.
+func chronyDial() (net.Conn, error) {
+	local := sysroot.Root("run", "chrony", fmt.Sprintf("gomon.%d.sock", os.Getpid()))
+	os.Remove(local)
+	conn, err := net.DialUnix(
+		"unixgram",
+		&net.UnixAddr{Name: local, Net: "unixgram"},
+		&net.UnixAddr{Name: sysroot.Root("run", "chrony", "chronyd.sock"), Net: "unixgram"},
+	)
+	if err == nil {
+		os.Chmod(local, 0666) // so that chronyd, if not root, may reply
+		return &chronyConn{UnixConn: conn, local: local}, nil
+	}
+
+	udp, err := net.Dial("udp", chronyAddress)
+	if err != nil {
+		return nil, gocore.Error("Dial", err)
+	}
+	return udp, nil
+}
+
+// chronyConn is a unix domain socket connection to the chrony daemon that removes its bound socket on close.
+type chronyConn struct {
+	*net.UnixConn
+	local string
+}
+
+// Close closes the connection and removes its bound socket.
+func (c *chronyConn) Close() error {
+	defer os.Remove(c.local)
+	return c.UnixConn.Close()
+}
+
+// parseTracking validates and decodes the chrony daemon's reply to a tracking request.
+func parseTracking(rpy []byte, seq uint32) (Tracking, error) {
+	if len(rpy) < chronyHeaderSize+chronyTrackingSize ||
+		rpy[0] != chronyVersion ||
+		rpy[1] != chronyReply ||
+		binary.BigEndian.Uint16(rpy[6:]) != chronyRpyTracking ||
+		binary.BigEndian.Uint32(rpy[16:]) != seq {
+		return Tracking{}, gocore.Error("chrony", fmt.Errorf("invalid tracking reply"))
+	}
+	if status := binary.BigEndian.Uint16(rpy[8:]); status != chronySuccess {
+		return Tracking{}, gocore.Error("chrony", fmt.Errorf("tracking request status %d", status))
+	}
+
+	d := rpy[chronyHeaderSize:]
+	t := Tracking{
+		Reference:      reference(binary.BigEndian.Uint32(d[0:]), d[4:24]),
+		Stratum:        int(binary.BigEndian.Uint16(d[24:])),
+		SystemOffset:   seconds(d[40:]),
+		LastOffset:     seconds(d[44:]),
+		RmsOffset:      seconds(d[48:]),
+		Frequency:      chronyFloat(d[52:]),
+		Skew:           chronyFloat(d[60:]),
+		RootDelay:      seconds(d[64:]),
+		RootDispersion: seconds(d[68:]),
+		UpdateInterval: seconds(d[72:]),
+	}
+	if leap := int(binary.BigEndian.Uint16(d[26:])); leap < len(chronyLeapStatus) {
+		t.LeapStatus = chronyLeapStatus[leap]
+	}
+	return t, nil
+}
+
+// reference formats the address of the reference clock, or its reference id for a local reference clock.
+func reference(id uint32, addr []byte) string {
+	switch binary.BigEndian.Uint16(addr[16:]) { // address family
+	case 1:
+		return netip.AddrFrom4([4]byte(addr[:4])).String()
+	case 2:
+		return netip.AddrFrom16([16]byte(addr[:16])).String()
+	}
+	var ref []byte
+	for i := 24; i >= 0; i -= 8 {
+		if c := byte(id >> i); c >= ' ' && c <= '~' {
+			ref = append(ref, c)
+		}
+	}
+	return string(ref)
+}
+
+// seconds converts a chrony floating point number of seconds to a duration.
+func seconds(b []byte) time.Duration {
+	return time.Duration(chronyFloat(b) * float64(time.Second))
+}
+
+// chronyFloat decodes chrony's 32-bit floating point encoding of a 7-bit exponent and 25-bit coefficient.
+func chronyFloat(b []byte) float64 {
+	x := binary.BigEndian.Uint32(b)
+	exp := int(x >> 25)
+	if exp >= 1<<6 {
+		exp -= 1 << 7
+	}
+	exp -= 25
+	coef := int(x % (1 << 25))
+	if coef >= 1<<24 {
+		coef -= 1 << 25
+	}
+	return float64(coef) * math.Pow(2, float64(exp))
+}
@@ -0,0 +1,155 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zosmac/gocore"
+)
+
+// trackingReply records a chrony tracking reply to the request with sequence number seq.
+func trackingReply(seq uint32) []byte {
+	rpy := make([]byte, chronyHeaderSize+chronyTrackingSize)
+	copy(rpy, []byte{
+		6, 2, 0, 0, // version, reply, reserved
+		0, 33, 0, 5, // tracking command, tracking reply
+		0, 0, 0, 0, // success status, pad
+		0, 0, 0, 0, // pad
+	})
+	binary.BigEndian.PutUint32(rpy[16:], seq)
+
+	copy(rpy[chronyHeaderSize:], []byte{
+		0xc0, 0x00, 0x02, 0x01, // reference id
+		192, 0, 2, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // address
+		0, 1, 0, 0, // IPv4 family, pad
+		0, 3, 0, 0, // stratum 3, leap status normal
+		0, 0, 0, 0, 0x66, 0xd1, 0xb6, 0x80, 0, 0, 0, 0, // reference time
+		0xdc, 0x80, 0x00, 0x00, // system offset 2^-20 seconds
+		0x03, 0x80, 0x00, 0x00, // last offset -0.5 seconds
+		0x04, 0x80, 0x00, 0x00, // rms offset 1 second
+		0x31, 0xff, 0xff, 0xe7, // frequency -12.5 ppm
+		0x04, 0x80, 0x00, 0x00, // residual frequency 1 ppm
+		0x04, 0x80, 0x00, 0x00, // skew 1 ppm
+		0x03, 0x80, 0x00, 0x00, // root delay -0.5 seconds
+		0xdc, 0x80, 0x00, 0x00, // root dispersion 2^-20 seconds
+		0x10, 0x80, 0x00, 0x00, // update interval 64 seconds
+	})
+	return rpy
+}
+
+// tracked is the decoding of trackingReply.
+var tracked = Tracking{
+	Reference:      "192.0.2.1",
+	Stratum:        3,
+	LeapStatus:     "normal",
+	SystemOffset:   953 * time.Nanosecond,
+	LastOffset:     -500 * time.Millisecond,
+	RmsOffset:      time.Second,
+	Frequency:      -12.5,
+	Skew:           1,
+	RootDelay:      -500 * time.Millisecond,
+	RootDispersion: 953 * time.Nanosecond,
+	UpdateInterval: 64 * time.Second,
+}
+
+func TestChronyFloat(t *testing.T) {
+	tests := []struct {
+		wire uint32
+		want float64
+	}{
+		{0x00000000, 0},
+		{0x04800000, 1},
+		{0x03800000, -0.5},
+		{0xdc800000, 1.0 / (1 << 20)},
+		{0x31ffffe7, -12.5},
+		{0x10800000, 64},
+	}
+
+	for _, tt := range tests {
+		b := binary.BigEndian.AppendUint32(nil, tt.wire)
+		if got := chronyFloat(b); got != tt.want {
+			t.Errorf("chronyFloat(%#08x) = %g, want %g", tt.wire, got, tt.want)
+		}
+	}
+}
+
+func TestReference(t *testing.T) {
+	ipv6 := make([]byte, 20)
+	copy(ipv6, []byte{0x20, 0x01, 0x0d, 0xb8, 15: 1})
+	ipv6[17] = 2
+	local := make([]byte, 20) // unspecified address of a local reference clock
+
+	tests := []struct {
+		id   uint32
+		addr []byte
+		want string
+	}{
+		{0x00000000, ipv6, "2001:db8::1"},
+		{0x47505300, local, "GPS"},
+		{0x50505300, local, "PPS"},
+	}
+
+	for _, tt := range tests {
+		if got := reference(tt.id, tt.addr); got != tt.want {
+			t.Errorf("reference(%#08x) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestParseTracking(t *testing.T) {
+	if got, err := parseTracking(trackingReply(42), 42); err != nil || got != tracked {
+		t.Errorf("parseTracking() = %+v, %v, want %+v", got, err, tracked)
+	}
+
+	if _, err := parseTracking(trackingReply(41), 42); err == nil {
+		t.Error("parseTracking() accepted reply to another request")
+	}
+	if _, err := parseTracking(trackingReply(42)[:chronyHeaderSize+chronyTrackingSize-1], 42); err == nil {
+		t.Error("parseTracking() accepted truncated reply")
+	}
+	failed := trackingReply(42)
+	failed[9] = 2 // unauthorised
+	if _, err := parseTracking(failed, 42); err == nil {
+		t.Error("parseTracking() accepted failed request")
+	}
+}
+
+func TestTracking(t *testing.T) {
+	// chronyd's control socket, reached through the root of the relocated procfs' init process
+	procfs := t.TempDir()
+	dirname := filepath.Join(procfs, "1", "root", "run", "chrony")
+	if err := os.MkdirAll(dirname, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gocore.Flags.FlagSet.Set("procfs", procfs)
+	defer gocore.Flags.FlagSet.Set("procfs", "/proc")
+
+	chronyd, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: filepath.Join(dirname, "chronyd.sock"), Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chronyd.Close()
+	go func() {
+		req := make([]byte, 512)
+		n, addr, err := chronyd.ReadFromUnix(req)
+		if err != nil || n != chronyHeaderSize+chronyTrackingSize ||
+			req[0] != chronyVersion || req[1] != chronyRequest ||
+			binary.BigEndian.Uint16(req[4:]) != chronyReqTracking {
+			return
+		}
+		chronyd.WriteToUnix(trackingReply(binary.BigEndian.Uint32(req[8:])), addr)
+	}()
+
+	if got, err := tracking(); err != nil || got != tracked {
+		t.Errorf("tracking() = %+v, %v, want %+v", got, err, tracked)
+	}
+	if ns, _ := filepath.Glob(filepath.Join(dirname, "gomon.*.sock")); len(ns) > 0 {
+		t.Errorf("tracking() left socket %v", ns)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package clock measures the health of the system clock for the "gomon" command. As gomon timestamps
+each message with the system's time, skew of the clock corrupts the correlation of messages across
+hosts. The measurement reports the kernel's time status (offset, frequency, estimated and maximum
+error, and synchronization state), the clocksource in use, the tracking of the chrony daemon, queried
+through its unix domain control socket or from localhost over UDP, and the entropy available for random
+number generation.
+
+A warning is logged when the clock loses synchronization or its offset exceeds the skew threshold.
+
+The clock package defines the following command line flag:
+* -skew: the clock offset above which to warn of clock skew
+
+The clock is reported for Linux only.
+*/
+package clock
@@ -0,0 +1,28 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"time"
+
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		skew time.Duration
+	}{
+		skew: 100 * time.Millisecond,
+	}
+)
+
+// init initializes the command line flags.
+func init() {
+	gocore.Flags.Var(
+		&flags.skew,
+		"skew",
+		"[-skew <offset>]",
+		"The clock `offset` above which to warn of clock skew (linux only)",
+	)
+}
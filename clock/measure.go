@@ -0,0 +1,69 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"fmt"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// kernel clock states.
+	stateSynchronized   = "synchronized"
+	stateUnsynchronized = "unsynchronized"
+)
+
+var (
+	// skewed and unsynchronized record the previous measurement's clock health to warn only on a change.
+	skewed, unsynchronized bool
+)
+
+// Measure captures the system clock's metrics.
+func Measure() []message.Content {
+	m := measure()
+	if m == nil {
+		return nil
+	}
+	m.Header = message.Measurement()
+	m.EventID.Name = "clock"
+	check(m)
+	return []message.Content{m}
+}
+
+// check warns when the clock loses synchronization or its offset exceeds the skew threshold, and reports recovery.
+func check(m *Measurement) {
+	offset := m.Kernel.Offset
+	if m.Daemon == "chronyd" && m.Tracking != (Tracking{}) {
+		offset = m.Tracking.SystemOffset
+	}
+	offset = max(offset, -offset)
+
+	if u := m.State == stateUnsynchronized; u != unsynchronized {
+		unsynchronized = u
+		if u {
+			gocore.Error("clock", fmt.Errorf("clock unsynchronized"), map[string]string{
+				"daemon": m.Daemon,
+			}).Warn()
+		} else {
+			gocore.Error("clock", nil, map[string]string{
+				"state":  m.State,
+				"daemon": m.Daemon,
+			}).Info()
+		}
+	}
+
+	if s := offset > flags.skew; s != skewed {
+		skewed = s
+		if s {
+			gocore.Error("clock", fmt.Errorf("clock skew %s exceeds %s", offset, flags.skew), map[string]string{
+				"daemon": m.Daemon,
+			}).Warn()
+		} else {
+			gocore.Error("clock", nil, map[string]string{
+				"offset": offset.String(),
+			}).Info()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zosmac/gomon/sysroot"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	// states maps the kernel clock states returned by adjtimex.
+	states = map[int]string{
+		unix.TIME_OK:    stateSynchronized,
+		unix.TIME_INS:   "insert leap second",
+		unix.TIME_DEL:   "delete leap second",
+		unix.TIME_OOP:   "leap second in progress",
+		unix.TIME_WAIT:  "leap second occurred",
+		unix.TIME_ERROR: stateUnsynchronized,
+	}
+)
+
+// measure reads the kernel's time status, the clocksource, time synchronization daemon status, and the entropy.
+func measure() *Measurement {
+	m := &Measurement{}
+
+	var tx unix.Timex
+	if state, err := unix.Adjtimex(&tx); err == nil {
+		m.State = states[state]
+		if tx.Status&unix.STA_UNSYNC != 0 {
+			m.State = stateUnsynchronized
+		}
+		offset := time.Duration(tx.Offset)
+		if tx.Status&unix.STA_NANO == 0 {
+			offset *= time.Microsecond
+		}
+		m.Kernel = Kernel{
+			Offset:         offset,
+			Frequency:      float64(tx.Freq) / 65536, // scaled ppm
+			MaxError:       time.Duration(tx.Maxerror) * time.Microsecond,
+			EstimatedError: time.Duration(tx.Esterror) * time.Microsecond,
+			TaiOffset:      time.Duration(tx.Tai) * time.Second,
+		}
+	}
+
+	dirname := sysroot.Sys("devices", "system", "clocksource", "clocksource0")
+	m.Clocksource = readString(dirname + "/current_clocksource")
+	m.AvailableClocksources = readString(dirname + "/available_clocksource")
+
+	if t, err := tracking(); err == nil {
+		m.Daemon = "chronyd"
+		m.Tracking = t
+	} else if _, err := os.Stat(sysroot.Root("run", "chrony", "chronyd.sock")); err == nil {
+		m.Daemon = "chronyd" // but its tracking is inaccessible
+	} else if _, err := os.Stat(sysroot.Root("run", "systemd", "timesync")); err == nil {
+		m.Daemon = "systemd-timesyncd"
+	}
+
+	m.Entropy = Entropy{
+		Available: readInt(sysroot.Proc("sys", "kernel", "random", "entropy_avail")),
+		PoolSize:  readInt(sysroot.Proc("sys", "kernel", "random", "poolsize")),
+	}
+
+	return m
+}
+
+// readString reads a single value file, such as those of procfs and sysfs.
+func readString(filename string) string {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// readInt reads a single integer value file, such as those of procfs and sysfs.
+func readInt(filename string) int {
+	n, _ := strconv.Atoi(readString(filename))
+	return n
+}
@@ -0,0 +1,8 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+// measure reads the system clock's metrics. Not reported on this platform.
+func measure() *Measurement {
+	return nil
+}
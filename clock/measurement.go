@@ -0,0 +1,83 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package clock
+
+import (
+	"time"
+
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Name string `json:"name" gomon:"property"`
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		State                 string `json:"state" gomon:"property"` // of the kernel clock, e.g. synchronized, unsynchronized
+		Clocksource           string `json:"clocksource" gomon:"property,,linux"`
+		AvailableClocksources string `json:"available_clocksources" gomon:"property,,linux"`
+		Daemon                string `json:"daemon,omitempty" gomon:"property"` // time synchronization daemon, e.g. chronyd
+	}
+
+	// Kernel contains the kernel's time status reported by adjtimex.
+	Kernel struct {
+		Offset         time.Duration `json:"offset" gomon:"gauge,ns"`
+		Frequency      float64       `json:"frequency" gomon:"gauge,ppm"`
+		MaxError       time.Duration `json:"max_error" gomon:"gauge,ns"`
+		EstimatedError time.Duration `json:"estimated_error" gomon:"gauge,ns"`
+		TaiOffset      time.Duration `json:"tai_offset,omitempty" gomon:"gauge,ns"`
+	}
+
+	// Tracking contains the chrony daemon's tracking of its reference clock.
+	Tracking struct {
+		Reference      string        `json:"reference,omitempty" gomon:"property"`
+		Stratum        int           `json:"stratum,omitempty" gomon:"gauge,count"`
+		LeapStatus     string        `json:"leap_status,omitempty" gomon:"property"`
+		SystemOffset   time.Duration `json:"system_offset,omitempty" gomon:"gauge,ns"` // being corrected by slewing
+		LastOffset     time.Duration `json:"last_offset,omitempty" gomon:"gauge,ns"`
+		RmsOffset      time.Duration `json:"rms_offset,omitempty" gomon:"gauge,ns"`
+		Frequency      float64       `json:"frequency,omitempty" gomon:"gauge,ppm"`
+		Skew           float64       `json:"skew,omitempty" gomon:"gauge,ppm"`
+		RootDelay      time.Duration `json:"root_delay,omitempty" gomon:"gauge,ns"`
+		RootDispersion time.Duration `json:"root_dispersion,omitempty" gomon:"gauge,ns"`
+		UpdateInterval time.Duration `json:"update_interval,omitempty" gomon:"gauge,ns"`
+	}
+
+	// Entropy contains the bits available to the kernel's random number generator.
+	Entropy struct {
+		Available int `json:"available" gomon:"gauge,bits,linux"`
+		PoolSize  int `json:"pool_size" gomon:"gauge,bits,linux"`
+	}
+
+	// Metrics defines measurement metrics.
+	Metrics struct {
+		Kernel   Kernel   `json:"kernel" gomon:""`
+		Tracking Tracking `json:"tracking" gomon:""`
+		Entropy  Entropy  `json:"entropy" gomon:""`
+	}
+
+	// Measurement defines the properties and metrics of a clock measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+		Metrics                              `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a clock message.
+func (m *Measurement) ID() string {
+	return m.EventID.Name
+}
@@ -16,6 +16,7 @@ import (
 	"unsafe"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
 )
 
 var (
@@ -26,9 +27,9 @@ var (
 	mountTypes, _ = gocore.MountMap()
 )
 
-// init initializes the local filesystem types and the mount types.
-func init() {
-	if f, err := os.Open("/proc/filesystems"); err == nil {
+// readLocalTypes initializes the local filesystem types.
+func readLocalTypes() {
+	if f, err := os.Open(sysroot.Proc("filesystems")); err == nil {
 		defer f.Close()
 		sc := bufio.NewScanner(f)
 		for sc.Scan() {
@@ -49,6 +50,8 @@ type handle struct {
 
 // open obtains a directory handle for observer.
 func open(directory string) (*handle, error) {
+	readLocalTypes()
+
 	fd, err := syscall.InotifyInit()
 	if err != nil {
 		return nil, gocore.Error("inotify_init", err)
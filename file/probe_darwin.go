@@ -0,0 +1,12 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package file
+
+import (
+	"github.com/zosmac/gomon/capability"
+)
+
+// Probe determines the availability of the file observation subsystem.
+func Probe() []capability.Capability {
+	return nil
+}
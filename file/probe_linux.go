@@ -0,0 +1,40 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package file
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/zosmac/gomon/capability"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+const (
+	// minWatches below which observing a directory hierarchy of files may exhaust the inotify watches.
+	minWatches = 65536
+)
+
+// Probe determines the availability of inotify for the file and logs observations.
+func Probe() []capability.Capability {
+	c := capability.Capability{Name: "inotify", Mode: capability.Full}
+	fd, err := syscall.InotifyInit()
+	if err != nil {
+		c.Mode = capability.Disabled
+		c.Detail = err.Error()
+		c.Observations = []string{"file", "logs"}
+		return []capability.Capability{c}
+	}
+	syscall.Close(fd)
+
+	if buf, err := os.ReadFile(sysroot.Proc("sys", "fs", "inotify", "max_user_watches")); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(string(buf))); err == nil && n < minWatches {
+			c.Mode = capability.Degraded
+			c.Detail = "max_user_watches " + strconv.Itoa(n) + " may limit files observed"
+		}
+	}
+
+	return []capability.Capability{c}
+}
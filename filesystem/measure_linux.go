@@ -6,19 +6,22 @@ import (
 	"bufio"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/zosmac/gocore"
 	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
 )
 
 var (
 	// deviceTypes used on this system.
-	deviceTypes = map[string]struct{}{}
+	deviceTypes     = map[string]struct{}{}
+	deviceTypesOnce sync.Once
 )
 
-// init builds a list of the filesystem device types.
-func init() {
-	f, err := os.Open("/proc/filesystems")
+// readDeviceTypes builds a list of the filesystem device types.
+func readDeviceTypes() {
+	f, err := os.Open(sysroot.Proc("filesystems"))
 	if err != nil {
 		return
 	}
@@ -32,9 +35,15 @@ func init() {
 
 // filesystems returns a list of filesystems.
 func filesystems() ([]message.Request, error) {
-	m, err := os.Open("/etc/mtab")
+	deviceTypesOnce.Do(readDeviceTypes)
+
+	mtab := "/etc/mtab"
+	if sysroot.Relocated() { // read the mounts of the host's init process
+		mtab = sysroot.Proc("1", "mounts")
+	}
+	m, err := os.Open(mtab)
 	if err != nil {
-		return nil, gocore.Error("Open /etc/mtab", err)
+		return nil, gocore.Error("Open "+mtab, err)
 	}
 	defer m.Close()
 
@@ -48,7 +57,7 @@ func filesystems() ([]message.Request, error) {
 		qs = append(qs,
 			func() []message.Content {
 				return []message.Content{
-					&measurement{
+					&Measurement{
 						Header: message.Measurement(),
 						EventID: EventID{
 							Mount: f[0],
@@ -51,7 +51,7 @@ func filesystems() ([]message.Request, error) {
 					uint32(len(target)-1),
 				)
 				return []message.Content{
-					&measurement{
+					&Measurement{
 						Header: message.Measurement(),
 						EventID: EventID{
 							Mount: drive,
@@ -13,10 +13,10 @@ var (
 		observations gocore.Options
 	}{
 		measurements: gocore.Options{
-			List: []string{"filesystem", "io", "network", "process", "system"},
+			List: []string{"clock", "filesystem", "interrupts", "inventory", "io", "listeners", "network", "numa", "process", "sensors", "system", "systemd"},
 		},
 		observations: gocore.Options{
-			List: []string{"file", "logs", "process"},
+			List: []string{"file", "inventory", "logs", "process", "sensors", "systemd"},
 		},
 	}
 )
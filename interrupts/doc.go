@@ -0,0 +1,19 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package interrupts measures the hardware interrupts per IRQ line and the software interrupts per
+class (e.g. NET_RX, TIMER, BLOCK) serviced by each CPU for the "gomon" command.
+
+For each line and class, and for all lines and all classes in total, an imbalance indicator
+reports the share of interrupts since the previous measurement serviced by the busiest CPU
+relative to an even share. A value of 1 indicates interrupts spread evenly across the CPUs,
+a value equal to the CPU count indicates a single CPU servicing all of them.
+
+Per CPU counts are reported for the totals only, unless the -interruptcpus flag is specified.
+
+Interrupts are reported for Linux only.
+
+The interrupts package defines the following command line flag:
+* -interruptcpus: report the per CPU counts of each IRQ line and softirq class
+*/
+package interrupts
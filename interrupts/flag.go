@@ -0,0 +1,24 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+import (
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		cpus bool
+	}{}
+)
+
+// init initializes the command line flags.
+func init() {
+	gocore.Flags.Var(
+		&flags.cpus,
+		"interruptcpus",
+		"[-interruptcpus]",
+		"Report the per CPU counts of each IRQ line and softirq class, not only of their totals",
+	)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// interrupt kinds.
+	kindIrq     = "irq"
+	kindSoftirq = "softirq"
+)
+
+var (
+	// prev records the per CPU counts of the previous measurement to derive the imbalance.
+	prev     = map[string]map[string]int{}
+	prevLock sync.Mutex
+)
+
+// Measure captures the system's interrupt metrics.
+func Measure() (ms []message.Content) {
+	prevLock.Lock()
+	defer prevLock.Unlock()
+
+	curr := map[string]map[string]int{}
+	for _, m := range measures() {
+		id := m.ID()
+		counts := make(map[string]int, len(m.Cpus))
+		for _, c := range m.Cpus {
+			counts[c.Id] = c.Count
+		}
+		curr[id] = counts
+		m.Imbalance = imbalance(prev[id], counts)
+		if m.Name != "total" && !flags.cpus {
+			m.Cpus = nil
+		}
+		ms = append(ms, m)
+	}
+	prev = curr
+
+	return
+}
+
+// total sums the per CPU counts of the measurements of a kind of interrupt.
+func total(kind string, ms []*Measurement) *Measurement {
+	t := &Measurement{
+		Header:  message.Measurement(),
+		EventID: EventID{Kind: kind, Name: "total"},
+	}
+	index := map[string]int{}
+	for _, m := range ms {
+		for _, c := range m.Cpus {
+			i, ok := index[c.Id]
+			if !ok {
+				i = len(t.Cpus)
+				index[c.Id] = i
+				t.Cpus = append(t.Cpus, Cpu{Id: c.Id})
+			}
+			t.Cpus[i].Count += c.Count
+		}
+		t.Total += m.Total
+	}
+	return t
+}
+
+// imbalance computes the busiest CPU's share of the interrupts since the previous measurement relative to an
+// even share, over the CPUs online for both measurements.
+func imbalance(prev, curr map[string]int) float64 {
+	var diffs []int
+	var sum int
+	for id, n := range curr {
+		if p, ok := prev[id]; ok {
+			diffs = append(diffs, n-p)
+			sum += n - p
+		}
+	}
+	if len(diffs) < 2 || sum <= 0 {
+		return 0
+	}
+	return float64(slices.Max(diffs)) * float64(len(diffs)) / float64(sum)
+}
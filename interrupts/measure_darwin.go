@@ -0,0 +1,8 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+// measures reads the interrupt counts. Not reported on this platform.
+func measures() []*Measurement {
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+// measures reads the interrupt counts of the IRQ lines and softirq classes.
+func measures() []*Measurement {
+	irqs := parse(sysroot.Proc("interrupts"), kindIrq)
+	softirqs := parse(sysroot.Proc("softirqs"), kindSoftirq)
+	ms := append(irqs, softirqs...)
+	if len(irqs) > 0 {
+		ms = append(ms, total(kindIrq, irqs))
+	}
+	if len(softirqs) > 0 {
+		ms = append(ms, total(kindSoftirq, softirqs))
+	}
+	return ms
+}
+
+// parse reads a table of interrupt counts per CPU, with a header line of CPU names.
+func parse(filename, kind string) []*Measurement {
+	f, err := os.Open(filename)
+	if err != nil {
+		gocore.Error(filename, err).Err()
+		return nil
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		return nil
+	}
+	ids := strings.Fields(strings.ToLower(sc.Text())) // header, e.g. CPU0 CPU1 CPU3, omitting offline CPUs
+
+	var ms []*Measurement
+	for sc.Scan() {
+		name, rest, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		m := &Measurement{
+			Header:  message.Measurement(),
+			EventID: EventID{Kind: kind, Name: strings.TrimSpace(name)},
+			Metrics: Metrics{Cpus: make([]Cpu, 0, len(ids))},
+		}
+		i := 0
+		for ; i < len(fields) && i < len(ids); i++ {
+			n, err := strconv.Atoi(fields[i])
+			if err != nil {
+				break
+			}
+			m.Cpus = append(m.Cpus, Cpu{Id: ids[i], Count: n})
+			m.Total += n
+		}
+		if len(m.Cpus) == 1 && len(ids) > 1 { // e.g. ERR and MIS report a single count for the system
+			m.Cpus = nil
+		}
+
+		// numbered IRQ lines report the chip, hardware irq and device; others a description
+		desc := fields[i:]
+		if _, err := strconv.Atoi(m.Name); err == nil && len(desc) > 2 {
+			m.Chip = desc[0]
+			m.Device = strings.Join(desc[2:], " ")
+		} else {
+			m.Device = strings.Join(desc, " ")
+		}
+
+		ms = append(ms, m)
+	}
+
+	return ms
+}
@@ -0,0 +1,95 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("procfs", "testdata/proc")
+	os.Exit(m.Run())
+}
+
+func TestParse(t *testing.T) {
+	ms := parse("testdata/proc/interrupts", kindIrq)
+	names := make([]string, len(ms))
+	for i, m := range ms {
+		names[i] = m.Name
+	}
+	if want := []string{"0", "8", "24", "NMI", "LOC", "ERR", "MIS"}; !slices.Equal(names, want) {
+		t.Fatalf("parse() names = %v, want %v", names, want)
+	}
+
+	// CPU2 is offline, so the third column is cpu3
+	want := []Cpu{{Id: "cpu0", Count: 100}, {Id: "cpu1", Count: 2900}, {Id: "cpu3", Count: 0}}
+	if m := ms[2]; !slices.Equal(m.Cpus, want) || m.Total != 3000 || m.Chip != "PCI-MSI" || m.Device != "nvme0q0, eth0" {
+		t.Errorf("parse() irq 24 = %+v", m)
+	}
+	if m := ms[3]; m.Total != 6 || m.Chip != "" || m.Device != "Non-maskable interrupts" {
+		t.Errorf("parse() NMI = %+v", m)
+	}
+	if m := ms[5]; m.Cpus != nil || m.Total != 0 {
+		t.Errorf("parse() ERR = %+v", m)
+	}
+}
+
+func TestTotal(t *testing.T) {
+	ms := parse("testdata/proc/softirqs", kindSoftirq)
+	tl := total(kindSoftirq, ms)
+	want := []Cpu{{Id: "cpu0", Count: 149399}, {Id: "cpu1", Count: 121000}, {Id: "cpu3", Count: 90500}}
+	if tl.Name != "total" || tl.Kind != kindSoftirq || !slices.Equal(tl.Cpus, want) || tl.Total != 360899 {
+		t.Errorf("total() = %+v", tl)
+	}
+}
+
+func TestImbalance(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, curr map[string]int
+		want       float64
+	}{
+		{"even", map[string]int{"cpu0": 0, "cpu1": 0}, map[string]int{"cpu0": 50, "cpu1": 50}, 1},
+		{"one cpu", map[string]int{"cpu0": 0, "cpu1": 0}, map[string]int{"cpu0": 100, "cpu1": 0}, 2},
+		{"first sample", nil, map[string]int{"cpu0": 100, "cpu1": 0}, 0},
+		{"no interrupts", map[string]int{"cpu0": 10, "cpu1": 10}, map[string]int{"cpu0": 10, "cpu1": 10}, 0},
+		{
+			"cpu offlined",
+			map[string]int{"cpu0": 0, "cpu1": 0, "cpu2": 0},
+			map[string]int{"cpu0": 30, "cpu1": 10},
+			1.5,
+		},
+		{
+			"cpu onlined",
+			map[string]int{"cpu0": 0, "cpu1": 0},
+			map[string]int{"cpu0": 30, "cpu1": 10, "cpu2": 1000},
+			1.5,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := imbalance(tt.prev, tt.curr); got != tt.want {
+			t.Errorf("%s: imbalance() = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	defer func(cpus bool) { flags.cpus = cpus }(flags.cpus)
+
+	for _, cpus := range []bool{false, true} {
+		flags.cpus = cpus
+		for _, c := range Measure() {
+			m := c.(*Measurement)
+			if reported := len(m.Cpus) > 0; m.Name == "total" && !reported {
+				t.Errorf("-interruptcpus=%t: %s total without per CPU counts", cpus, m.Kind)
+			} else if m.Name != "total" && reported != cpus && m.Name != "ERR" && m.Name != "MIS" {
+				t.Errorf("-interruptcpus=%t: %s %s per CPU counts %v", cpus, m.Kind, m.Name, m.Cpus)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package interrupts
+
+import (
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Kind string `json:"kind" gomon:"property"` // irq or softirq
+		Name string `json:"name" gomon:"property"` // IRQ line or softirq class, or "total"
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		Chip   string `json:"chip,omitempty" gomon:"property"`
+		Device string `json:"device,omitempty" gomon:"property"`
+	}
+
+	// Cpu contains the interrupt count of a CPU.
+	Cpu struct {
+		Id    string `json:"id" gomon:"property"` // e.g. cpu0, as named by the header line of /proc/interrupts
+		Count int    `json:"count" gomon:"counter,count"`
+	}
+
+	// Metrics defines measurement metrics.
+	Metrics struct {
+		Total     int     `json:"total" gomon:"counter,count"`
+		Cpus      []Cpu   `json:"cpus,omitempty" gomon:""`                // of the totals, or with the -interruptcpus flag
+		Imbalance float64 `json:"imbalance,omitempty" gomon:"gauge,none"` // busiest CPU's share relative to an even share
+	}
+
+	// Measurement defines the properties and metrics of an interrupts measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+		Metrics                              `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for an interrupts message.
+func (m *Measurement) ID() string {
+	return m.EventID.Kind + ":" + m.EventID.Name
+}
+
+// Labels returns the labels that distinguish the interrupt counts of an individual CPU.
+func (c Cpu) Labels() map[string]string {
+	return map[string]string{
+		"cpu": c.Id,
+	}
+}
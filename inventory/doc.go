@@ -0,0 +1,15 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package inventory measures the configuration of the host's operating system for the "gomon" command.
+The inventory includes the kernel version, the distribution, the boot parameters, the loaded kernel
+modules, selected kernel parameters (sysctls), the machine id, the virtualization and container
+environment, and the time synchronization status. As this changes rarely, the inventory is reported
+at a low frequency. When observed, a change of any value is reported promptly to reveal configuration
+drift across a fleet of hosts.
+
+The inventory package defines the following command line flags:
+* -inventory: the interval between reports of the inventory measurement
+* -sysctls: a comma-separated list of kernel parameters to report (linux only)
+*/
+package inventory
@@ -0,0 +1,68 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"strings"
+	"time"
+
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		interval time.Duration
+		sysctls  sysctls
+	}{
+		interval: time.Hour,
+		sysctls: sysctls{
+			"fs.file-max",
+			"kernel.pid_max",
+			"kernel.threads-max",
+			"net.core.somaxconn",
+			"net.ipv4.ip_forward",
+			"net.ipv4.tcp_congestion_control",
+			"vm.max_map_count",
+			"vm.overcommit_memory",
+			"vm.swappiness",
+		},
+	}
+)
+
+type (
+	// sysctls is a command line flag type.
+	sysctls []string
+)
+
+// Set is a flag.Value interface method to enable sysctls as a command line flag.
+func (ss *sysctls) Set(s string) error {
+	*ss = nil
+	for _, s := range strings.Split(s, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*ss = append(*ss, s)
+		}
+	}
+	return nil
+}
+
+// String is a flag.Value interface method to enable sysctls as a command line flag.
+func (ss sysctls) String() string {
+	return strings.Join(ss, ",")
+}
+
+// init initializes the command line flags.
+func init() {
+	gocore.Flags.Var(
+		&flags.interval,
+		"inventory",
+		"[-inventory <interval>]",
+		"The `interval` between reports of the operating system inventory measurement",
+	)
+	gocore.Flags.Var(
+		&flags.sysctls,
+		"sysctls",
+		"[-sysctls <name>,...]",
+		"A comma-separated list of kernel parameter `names` to include in the inventory (linux only)",
+	)
+}
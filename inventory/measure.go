@@ -0,0 +1,54 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"time"
+
+	"github.com/zosmac/gomon/message"
+)
+
+var (
+	// reported is the time of the most recent inventory measurement.
+	reported time.Time
+)
+
+// Measure captures the operating system inventory, at most once per inventory interval.
+func Measure() []message.Content {
+	if time.Since(reported) < flags.interval {
+		return nil
+	}
+	m := inventory()
+	if m == nil {
+		return nil
+	}
+	reported = time.Now()
+	m.Header = message.Measurement()
+	return []message.Content{m}
+}
+
+// values flattens the inventory properties to a map of names to values for detecting changes.
+func (p *Properties) values() map[string]string {
+	vs := map[string]string{
+		"kernel.type":              p.Kernel.Type,
+		"kernel.release":           p.Kernel.Release,
+		"kernel.version":           p.Kernel.Version,
+		"kernel.machine":           p.Kernel.Machine,
+		"distribution.id":          p.Distribution.Id,
+		"distribution.name":        p.Distribution.Name,
+		"distribution.version":     p.Distribution.Version,
+		"distribution.pretty_name": p.Distribution.PrettyName,
+		"cmdline":                  p.Cmdline,
+		"machine_id":               p.MachineId,
+		"virtualization":           p.Virtualization,
+		"container":                p.Container,
+		"time_sync":                p.TimeSync,
+	}
+	for _, mod := range p.Modules {
+		vs["module."+mod] = "loaded"
+	}
+	for name, val := range p.Sysctls {
+		vs["sysctl."+name] = val
+	}
+	return vs
+}
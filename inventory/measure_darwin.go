@@ -0,0 +1,45 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// inventory reads the operating system inventory.
+func inventory() *Measurement {
+	host, _ := unix.Sysctl("kern.hostname")
+	ostype, _ := unix.Sysctl("kern.ostype")
+	release, _ := unix.Sysctl("kern.osrelease")
+	version, _ := unix.Sysctl("kern.version")
+	machine, _ := unix.Sysctl("hw.machine")
+	product, _ := unix.Sysctl("kern.osproductversion")
+	bootargs, _ := unix.Sysctl("kern.bootargs")
+	uuid, _ := unix.Sysctl("kern.uuid")
+
+	virtualization := "none"
+	if vmm, err := unix.SysctlUint32("kern.hv_vmm_present"); err == nil && vmm != 0 {
+		virtualization = "unknown"
+	}
+
+	return &Measurement{
+		EventID: EventID{
+			Host: host,
+		},
+		Properties: Properties{
+			Kernel: Kernel{
+				Type:    ostype,
+				Release: release,
+				Version: version,
+				Machine: machine,
+			},
+			Distribution: Distribution{
+				Name:    "macOS",
+				Version: product,
+			},
+			Cmdline:        bootargs,
+			MachineId:      uuid,
+			Virtualization: virtualization,
+		},
+	}
+}
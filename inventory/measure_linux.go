@@ -0,0 +1,215 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
+	"golang.org/x/sys/unix"
+)
+
+var (
+	// hypervisors maps DMI system vendor and product names to virtualization technologies.
+	hypervisors = []struct {
+		match string
+		name  string
+	}{
+		{"KVM", "kvm"},
+		{"QEMU", "qemu"},
+		{"VMware", "vmware"},
+		{"VirtualBox", "oracle"},
+		{"Xen", "xen"},
+		{"Microsoft Corporation Virtual Machine", "microsoft"},
+		{"Amazon EC2", "amazon"},
+		{"Google Compute Engine", "google"},
+		{"Parallels", "parallels"},
+		{"Bochs", "bochs"},
+	}
+
+	// runtimes maps cgroup path elements to container runtimes.
+	runtimes = []struct {
+		match string
+		name  string
+	}{
+		{"kubepods", "kubernetes"},
+		{"docker", "docker"},
+		{"libpod", "podman"},
+		{"lxc", "lxc"},
+	}
+)
+
+// inventory reads the operating system inventory.
+func inventory() *Measurement {
+	m := &Measurement{
+		EventID: EventID{
+			Host: readString(sysroot.Proc("sys", "kernel", "hostname")),
+		},
+		Properties: Properties{
+			Kernel: Kernel{
+				Type:    readString(sysroot.Proc("sys", "kernel", "ostype")),
+				Release: readString(sysroot.Proc("sys", "kernel", "osrelease")),
+				Version: readString(sysroot.Proc("sys", "kernel", "version")),
+				Machine: machine(),
+			},
+			Distribution:   distribution(),
+			Cmdline:        readString(sysroot.Proc("cmdline")),
+			Modules:        modules(),
+			Sysctls:        map[string]string{},
+			MachineId:      readString(sysroot.Root("etc", "machine-id")),
+			Virtualization: virtualization(),
+			Container:      container(),
+			TimeSync:       timeSync(),
+		},
+	}
+
+	for _, name := range flags.sysctls {
+		if val := readString(sysroot.Proc("sys", strings.ReplaceAll(name, ".", "/"))); val != "" {
+			m.Sysctls[name] = strings.Join(strings.Fields(val), " ")
+		}
+	}
+
+	return m
+}
+
+// readString reads a single value file, such as those of procfs and sysfs.
+func readString(name string) string {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// machine reads the hardware architecture of the kernel. Kernels that predate the arch sysctl report it
+// only through uname, which describes gomon's own host, not that of a relocated procfs.
+func machine() string {
+	if arch := readString(sysroot.Proc("sys", "kernel", "arch")); arch != "" || sysroot.Relocated() {
+		return arch
+	}
+	var uname unix.Utsname
+	unix.Uname(&uname)
+	return gocore.GoStringN(&uname.Machine[0], len(uname.Machine))
+}
+
+// distribution reads the os-release file.
+func distribution() Distribution {
+	f, err := os.Open(sysroot.Root("etc", "os-release"))
+	if err != nil {
+		if f, err = os.Open(sysroot.Root("usr", "lib", "os-release")); err != nil {
+			return Distribution{}
+		}
+	}
+	defer f.Close()
+
+	var d Distribution
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, val, ok := strings.Cut(sc.Text(), "=")
+		if !ok {
+			continue
+		}
+		val = strings.Trim(val, `"'`)
+		switch key {
+		case "ID":
+			d.Id = val
+		case "NAME":
+			d.Name = val
+		case "VERSION_ID":
+			d.Version = val
+		case "PRETTY_NAME":
+			d.PrettyName = val
+		}
+	}
+	return d
+}
+
+// modules lists the names of the loaded kernel modules.
+func modules() []string {
+	f, err := os.Open(sysroot.Proc("modules"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var mods []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if name, _, ok := strings.Cut(sc.Text(), " "); ok {
+			mods = append(mods, name)
+		}
+	}
+	slices.Sort(mods)
+	return mods
+}
+
+// virtualization identifies the hypervisor on which the host runs.
+func virtualization() string {
+	if _, err := os.Stat(sysroot.Proc("xen")); err == nil {
+		return "xen"
+	}
+	dmi := readString(sysroot.Sys("class", "dmi", "id", "sys_vendor")) + " " +
+		readString(sysroot.Sys("class", "dmi", "id", "product_name"))
+	for _, h := range hypervisors {
+		if strings.Contains(dmi, h.match) {
+			return h.name
+		}
+	}
+
+	f, err := os.Open(sysroot.Proc("cpuinfo"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if key, val, ok := strings.Cut(sc.Text(), ":"); ok && strings.TrimSpace(key) == "flags" {
+			if slices.Contains(strings.Fields(val), "hypervisor") {
+				return "unknown"
+			}
+			break
+		}
+	}
+	return "none"
+}
+
+// container identifies the container runtime in which the host's init process runs.
+func container() string {
+	if buf, err := os.ReadFile(sysroot.Proc("1", "environ")); err == nil {
+		for _, env := range strings.Split(string(buf), "\000") {
+			if val, ok := strings.CutPrefix(env, "container="); ok {
+				return val
+			}
+		}
+	}
+	if _, err := os.Stat(sysroot.Root(".dockerenv")); err == nil {
+		return "docker"
+	}
+	if _, err := os.Stat(sysroot.Root("run", ".containerenv")); err == nil {
+		return "podman"
+	}
+	cgroup := readString(sysroot.Proc("1", "cgroup"))
+	for _, r := range runtimes {
+		if strings.Contains(cgroup, r.match) {
+			return r.name
+		}
+	}
+	return "none"
+}
+
+// timeSync reports whether the kernel considers the system clock synchronized.
+func timeSync() string {
+	var tx unix.Timex
+	state, err := unix.Adjtimex(&tx)
+	if err != nil {
+		return ""
+	}
+	if state == unix.TIME_ERROR || tx.Status&unix.STA_UNSYNC != 0 {
+		return "unsynchronized"
+	}
+	return "synchronized"
+}
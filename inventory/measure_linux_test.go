@@ -0,0 +1,51 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("procfs", "testdata/proc")
+	gocore.Flags.FlagSet.Set("sysfs", "testdata/sys")
+	gocore.Flags.FlagSet.Set("sysctls", "kernel.pid_max,vm.swappiness,net.core.somaxconn")
+	os.Exit(m.Run())
+}
+
+func TestInventory(t *testing.T) {
+	m := inventory()
+	if m.EventID.Host != "host1" {
+		t.Errorf("inventory() host = %q, want %q", m.EventID.Host, "host1")
+	}
+
+	p := m.Properties
+	if want := (Kernel{
+		Type:    "Linux",
+		Release: "6.8.0-45-generic",
+		Version: "#45-Ubuntu SMP PREEMPT_DYNAMIC Fri Aug 30 12:02:04 UTC 2024",
+		Machine: "aarch64", // of the relocated host, not of this one
+	}); p.Kernel != want {
+		t.Errorf("inventory() kernel = %+v, want %+v", p.Kernel, want)
+	}
+	if want := (Distribution{Id: "ubuntu", Name: "Ubuntu", Version: "24.04", PrettyName: "Ubuntu 24.04.1 LTS"}); p.Distribution != want {
+		t.Errorf("inventory() distribution = %+v, want %+v", p.Distribution, want)
+	}
+	if want := "BOOT_IMAGE=/vmlinuz-6.8.0-45-generic root=/dev/vda1 ro quiet"; p.Cmdline != want {
+		t.Errorf("inventory() cmdline = %q, want %q", p.Cmdline, want)
+	}
+	if want := []string{"nf_conntrack", "overlay", "virtio_net"}; !slices.Equal(p.Modules, want) {
+		t.Errorf("inventory() modules = %v, want %v", p.Modules, want)
+	}
+	if want := map[string]string{"kernel.pid_max": "4194304", "vm.swappiness": "60"}; !maps.Equal(p.Sysctls, want) {
+		t.Errorf("inventory() sysctls = %v, want %v", p.Sysctls, want)
+	}
+	if p.MachineId != "5b1f0e3c9a7d4e2f8c6b0a1d2e3f4a5b" || p.Virtualization != "qemu" || p.Container != "podman" {
+		t.Errorf("inventory() machine id %q, virtualization %q, container %q", p.MachineId, p.Virtualization, p.Container)
+	}
+}
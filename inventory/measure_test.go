@@ -0,0 +1,42 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	p := Properties{
+		Kernel:       Kernel{Type: "Linux", Release: "6.8.0", Version: "#45", Machine: "x86_64"},
+		Distribution: Distribution{Id: "ubuntu", Name: "Ubuntu", Version: "24.04", PrettyName: "Ubuntu 24.04.1 LTS"},
+		Cmdline:      "ro quiet",
+		Modules:      []string{"overlay", "virtio_net"},
+		Sysctls:      map[string]string{"vm.swappiness": "60"},
+		MachineId:    "5b1f",
+		Container:    "none",
+		TimeSync:     "synchronized",
+	}
+	want := map[string]string{
+		"kernel.type":              "Linux",
+		"kernel.release":           "6.8.0",
+		"kernel.version":           "#45",
+		"kernel.machine":           "x86_64",
+		"distribution.id":          "ubuntu",
+		"distribution.name":        "Ubuntu",
+		"distribution.version":     "24.04",
+		"distribution.pretty_name": "Ubuntu 24.04.1 LTS",
+		"cmdline":                  "ro quiet",
+		"machine_id":               "5b1f",
+		"virtualization":           "",
+		"container":                "none",
+		"time_sync":                "synchronized",
+		"module.overlay":           "loaded",
+		"module.virtio_net":        "loaded",
+		"sysctl.vm.swappiness":     "60",
+	}
+	if got := p.values(); !maps.Equal(got, want) {
+		t.Errorf("values() = %v, want %v", got, want)
+	}
+}
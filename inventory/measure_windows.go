@@ -0,0 +1,8 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+// inventory reads the operating system inventory. Not reported on this platform.
+func inventory() *Measurement {
+	return nil
+}
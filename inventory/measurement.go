@@ -0,0 +1,64 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Host string `json:"host" gomon:"property"`
+	}
+
+	// Kernel identifies the operating system kernel.
+	Kernel struct {
+		Type    string `json:"type" gomon:"property"`
+		Release string `json:"release" gomon:"property"`
+		Version string `json:"version" gomon:"property"`
+		Machine string `json:"machine" gomon:"property"`
+	}
+
+	// Distribution identifies the operating system distribution.
+	Distribution struct {
+		Id         string `json:"id" gomon:"property,,linux"`
+		Name       string `json:"name" gomon:"property"`
+		Version    string `json:"version" gomon:"property"`
+		PrettyName string `json:"pretty_name" gomon:"property,,linux"`
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		Kernel         Kernel            `json:"kernel" gomon:""`
+		Distribution   Distribution      `json:"distribution" gomon:""`
+		Cmdline        string            `json:"cmdline" gomon:"property,,!windows"`
+		Modules        []string          `json:"modules" gomon:"property,,linux"`
+		Sysctls        map[string]string `json:"sysctls" gomon:"property,,linux"`
+		MachineId      string            `json:"machine_id" gomon:"property,,!windows"`
+		Virtualization string            `json:"virtualization" gomon:"property,,!windows"`
+		Container      string            `json:"container" gomon:"property,,linux"`
+		TimeSync       string            `json:"time_sync" gomon:"property,,linux"`
+	}
+
+	// Measurement defines the properties of an operating system inventory measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for an inventory message.
+func (m *Measurement) ID() string {
+	return m.EventID.Host
+}
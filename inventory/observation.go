@@ -0,0 +1,49 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Observation{})
+}
+
+type (
+	// inventoryEvent type.
+	inventoryEvent string
+
+	// Observation defines the properties of an inventory change message.
+	Observation struct {
+		message.Header[inventoryEvent] `gomon:""`
+		EventID                        `json:"event_id" gomon:""`
+		Name                           string `json:"name" gomon:"property"`
+		Previous                       string `json:"previous" gomon:"property"`
+		Current                        string `json:"current" gomon:"property"`
+		Message                        string `json:"message" gomon:"property"`
+	}
+)
+
+const (
+	// message events.
+	inventoryChanged inventoryEvent = "changed"
+)
+
+var (
+	// inventoryEvents valid event values for messages.
+	inventoryEvents = gocore.ValidValue[inventoryEvent]{}.Define(
+		inventoryChanged,
+	)
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Observation) Events() []string {
+	return inventoryEvents.ValidValues()
+}
+
+// ID returns the identifier for an inventory change message.
+func (obs *Observation) ID() string {
+	return obs.EventID.Host
+}
@@ -0,0 +1,80 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// poll is the interval for reading the inventory for changes.
+	poll = time.Minute
+)
+
+// Observer starts capture of inventory change observations.
+func Observer(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		var prev map[string]string
+		for {
+			if m := inventory(); m != nil {
+				curr := m.values()
+				if obs := changes(m.EventID, prev, curr); prev != nil && len(obs) > 0 {
+					message.Observations(obs)
+				}
+				prev = curr
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// changes reports the inventory values that differ between successive readings.
+func changes(id EventID, prev, curr map[string]string) (obs []message.Content) {
+	names := slices.Sorted(maps.Keys(curr))
+	for name := range prev {
+		if _, ok := curr[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		p, c := prev[name], curr[name]
+		if p == c {
+			continue
+		}
+		var msg string
+		switch {
+		case p == "":
+			msg = fmt.Sprintf("%s added with value %q", name, c)
+		case c == "":
+			msg = fmt.Sprintf("%s removed, was %q", name, p)
+		default:
+			msg = fmt.Sprintf("%s changed from %q to %q", name, p, c)
+		}
+		obs = append(obs, &Observation{
+			Header:   message.Observation(time.Now(), inventoryChanged),
+			EventID:  id,
+			Name:     name,
+			Previous: p,
+			Current:  c,
+			Message:  msg,
+		})
+	}
+	return
+}
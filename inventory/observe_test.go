@@ -0,0 +1,45 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package inventory
+
+import (
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	prev := map[string]string{
+		"kernel.release":       "6.8.0-44-generic",
+		"module.overlay":       "loaded",
+		"sysctl.vm.swappiness": "60",
+	}
+	curr := map[string]string{
+		"kernel.release":       "6.8.0-45-generic",
+		"module.virtio_net":    "loaded",
+		"sysctl.vm.swappiness": "60",
+	}
+	want := []struct {
+		name, previous, current, message string
+	}{
+		{"kernel.release", "6.8.0-44-generic", "6.8.0-45-generic", `kernel.release changed from "6.8.0-44-generic" to "6.8.0-45-generic"`},
+		{"module.virtio_net", "", "loaded", `module.virtio_net added with value "loaded"`},
+		{"module.overlay", "loaded", "", `module.overlay removed, was "loaded"`},
+	}
+
+	id := EventID{Host: "host1"}
+	obs := changes(id, prev, curr)
+	if len(obs) != len(want) {
+		t.Fatalf("changes() = %d observations, want %d", len(obs), len(want))
+	}
+	for i, o := range obs {
+		o := o.(*Observation)
+		w := want[i]
+		if o.EventID != id || o.Event != inventoryChanged ||
+			o.Name != w.name || o.Previous != w.previous || o.Current != w.current || o.Message != w.message {
+			t.Errorf("changes()[%d] = %+v, want %+v", i, o, w)
+		}
+	}
+
+	if obs := changes(id, curr, curr); len(obs) != 0 {
+		t.Errorf("changes() of unchanged inventory = %d observations", len(obs))
+	}
+}
@@ -6,16 +6,16 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
 )
 
 // Measure captures system's I/O metrics.
 func Measure() (ms []message.Content) {
-	f, err := os.Open("/proc/diskstats")
+	f, err := os.Open(sysroot.Proc("diskstats"))
 	if err != nil {
 		return
 	}
@@ -34,9 +34,9 @@ func Measure() (ms []message.Content) {
 		fmt.Sscanf(sc.Text(), "%s %s %s %d %d %d %d %d %d %d %d %d %d %d", flds...)
 
 		var statfs syscall.Statfs_t
-		syscall.Statfs(filepath.Join("/dev", strs[2]), &statfs)
+		syscall.Statfs(sysroot.Root("dev", strs[2]), &statfs)
 
-		ms = append(ms, &measurement{
+		ms = append(ms, &Measurement{
 			Header: message.Measurement(),
 			EventID: EventID{
 				Device: strs[2],
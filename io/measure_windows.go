@@ -208,7 +208,7 @@ func Measure() (ms []message.Content) {
 			writeBytes += uint64(stats.UserFileWriteBytes)
 		}
 
-		ms = append(ms, &measurement{
+		ms = append(ms, &Measurement{
 			Header: message.Measurement(),
 			EventID: EventID{
 				Device: windows.UTF16ToString(target[:l]),
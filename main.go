@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"os"
 	"slices"
@@ -11,11 +12,15 @@ import (
 	"strings"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/capability"
 	"github.com/zosmac/gomon/file"
+	"github.com/zosmac/gomon/inventory"
 	"github.com/zosmac/gomon/logs"
 	"github.com/zosmac/gomon/message"
 	"github.com/zosmac/gomon/process"
+	"github.com/zosmac/gomon/sensors"
 	"github.com/zosmac/gomon/serve"
+	"github.com/zosmac/gomon/systemd"
 )
 
 // main
@@ -33,10 +38,47 @@ func Main(ctx context.Context) error {
 		return nil
 	}
 
+	// probe which subsystems are available and disable those that are not
+	capability.Record(process.Probe()...)
+	capability.Record(file.Probe()...)
+	capability.Record(systemd.Probe()...)
+	capability.Degrade(&flags.measurements, &flags.observations)
+
 	if err := message.Encoder(ctx); err != nil {
 		return gocore.Error("encoder", err)
 	}
 
+	// start lsof and open the process connector and taskstats sockets while gomon has root authority
+	if slices.Contains(flags.measurements.Selected, "process") ||
+		slices.Contains(flags.measurements.Selected, "listeners") ||
+		slices.Contains(flags.measurements.Selected, "system") ||
+		slices.Contains(flags.observations.Selected, "process") {
+		if err := process.Open(ctx,
+			slices.Contains(flags.measurements.Selected, "process") ||
+				slices.Contains(flags.measurements.Selected, "listeners"),
+			slices.Contains(flags.observations.Selected, "process"),
+		); err != nil {
+			return gocore.Error("process connections", err)
+		}
+	}
+
+	// relinquish root authority once the privileged resources are open
+	if err := capability.Drop(); err != nil {
+		return gocore.Error("runas", err)
+	}
+
+	// probe again to report the capabilities that remain after relinquishing root authority, and
+	// disable those lost before starting the observers
+	capability.Record(process.Probe()...)
+	capability.Record(file.Probe()...)
+	capability.Degrade(&flags.measurements, &flags.observations)
+
+	if slices.Contains(flags.observations.Selected, "inventory") {
+		if err := inventory.Observer(ctx); err != nil {
+			return gocore.Error("inventory Observer", err)
+		}
+	}
+
 	if slices.Contains(flags.observations.Selected, "logs") {
 		if err := logs.Observer(ctx); err != nil {
 			return gocore.Error("logs Observer", err)
@@ -53,6 +95,23 @@ func Main(ctx context.Context) error {
 		if err := process.Observer(ctx); err != nil {
 			return gocore.Error("processes Observer", err)
 		}
+		if process.Fallback() &&
+			!slices.Contains(flags.measurements.Selected, "process") &&
+			!slices.Contains(flags.measurements.Selected, "system") {
+			gocore.Error("processes Observer", errors.New("process table differences require the process or system measurement")).Warn()
+		}
+	}
+
+	if slices.Contains(flags.observations.Selected, "sensors") {
+		if err := sensors.Observer(ctx); err != nil {
+			return gocore.Error("sensors Observer", err)
+		}
+	}
+
+	if slices.Contains(flags.observations.Selected, "systemd") {
+		if err := systemd.Observer(ctx); err != nil {
+			return gocore.Error("systemd Observer", err)
+		}
 	}
 
 	// fire up the http server
@@ -60,11 +119,12 @@ func Main(ctx context.Context) error {
 
 	executable, _ := os.Executable()
 	settings := map[string]string{
-		"pid":        strconv.Itoa(os.Getpid()),
-		"command":    strings.Join(os.Args, " "),
-		"executable": executable,
-		"version":    gocore.Version,
-		"user":       gocore.Username(os.Getuid()),
+		"pid":          strconv.Itoa(os.Getpid()),
+		"command":      strings.Join(os.Args, " "),
+		"executable":   executable,
+		"version":      gocore.Version,
+		"user":         gocore.Username(os.Getuid()),
+		"capabilities": capability.Summary(),
 	}
 	gocore.Flags.FlagSet.VisitAll(func(f *flag.Flag) {
 		settings[f.Name] = f.Value.String()
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -92,13 +93,13 @@ func encode(ctx context.Context) {
 	for {
 		select {
 		case ms := <-measureChan:
-			fileEncode(ms)
+			fileEncode(withoutEnvs("file", ms))
 		case ms := <-observeChan:
 			if loki {
-				loki = lokiEncode(ms)
+				loki = lokiEncode(withoutEnvs("loki", ms))
 			}
 			if !loki {
-				fileEncode(ms)
+				fileEncode(withoutEnvs("file", ms))
 			}
 		case <-lokiTick:
 			if !loki {
@@ -117,6 +118,21 @@ func encode(ctx context.Context) {
 	}
 }
 
+// withoutEnvs removes process environments from content destined for a sink not selected to report them.
+func withoutEnvs(sink string, ms []Content) []Content {
+	if slices.Contains(flags.envs.Selected, sink) {
+		return ms
+	}
+	cs := make([]Content, len(ms))
+	for i, m := range ms {
+		if e, ok := m.(Environments); ok {
+			m = e.WithoutEnvs()
+		}
+		cs[i] = m
+	}
+	return cs
+}
+
 // fileEncode encodes the content for writing to file.
 func fileEncode(ms []Content) {
 	if flags.rotate.interval == 0 {
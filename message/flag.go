@@ -14,8 +14,12 @@ var (
 		document bool
 		protobuf bool
 		pretty   bool
+		envs     gocore.Options
 		rotate
 	}{
+		envs: gocore.Options{
+			List: []string{"file", "loki"},
+		},
 		rotate: rotate{interval: 0 * time.Hour},
 	}
 )
@@ -78,6 +82,12 @@ func init() {
 		"[-pretty]",
 		"Produce output in human readable format",
 	)
+	gocore.Flags.Var(
+		&flags.envs,
+		"envs",
+		"-envs", // options list added by gocore.Flags
+		"A comma-separated list of `sinks` to which to report process environments (Prometheus and the nodegraph never report them)",
+	)
 
 	var def string
 	if flags.rotate.interval == 0 {
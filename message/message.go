@@ -41,6 +41,12 @@ type (
 		Events() []string
 		ID() string
 	}
+
+	// Environments is implemented by content that reports process environments,
+	// so that sinks may omit them.
+	Environments interface {
+		WithoutEnvs() Content
+	}
 )
 
 const (
@@ -107,8 +113,7 @@ func Define(m Content) {
 			return messageField(m, name, tag, val)
 		},
 	)
-	src := filepath.Base(reflect.ValueOf(m).Elem().Type().PkgPath())
-	k := src + " |" + strings.Join(m.Events(), "|")
+	k := messageKey(m)
 	Messages[k] = make([]field, len(fs))
 	for i, f := range fs {
 		Messages[k][i] = f.(field)
@@ -116,6 +121,17 @@ func Define(m Content) {
 	}
 }
 
+// messageKey identifies a message by its source and events. A source that defines messages other than
+// its Measurement and Observation qualifies these by their type, e.g. "process.Thread".
+func messageKey(m Content) string {
+	t := reflect.ValueOf(m).Elem().Type()
+	src := filepath.Base(t.PkgPath())
+	if t.Name() != "Measurement" && t.Name() != "Observation" {
+		src += "." + t.Name()
+	}
+	return src + " |" + strings.Join(m.Events(), "|")
+}
+
 // messageField interprets a gomon tag for each message field.
 func messageField(m Content, name, tag string, val reflect.Value) field {
 	if max.Name < len(name) {
@@ -132,7 +148,7 @@ func messageField(m Content, name, tag string, val reflect.Value) field {
 		u = s[1]
 	}
 
-	key := filepath.Base(reflect.ValueOf(m).Elem().Type().PkgPath()) + " |" + strings.Join(m.Events(), "|")
+	key := messageKey(m)
 
 	switch t {
 	case "":
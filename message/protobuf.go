@@ -136,13 +136,16 @@ func messagesFunctions(messages *strings.Builder, functions *strings.Builder) {
 				functions.WriteString("  }\n  return b.Build()\n}\n")
 			}
 			key := strings.Split(f.key, " |")
-			event := "Measurement"
-			if len(key) > 1 && key[1] != "measure" {
-				event = "Observation"
+			src, event, ok := strings.Cut(key[0], ".")
+			if !ok {
+				event = "Measurement"
+				if len(key) > 1 && key[1] != "measure" {
+					event = "Observation"
+				}
 			}
-			typ := gocore.Capitalize(key[0]) + event
+			typ := gocore.Capitalize(src) + gocore.Capitalize(event)
 
-			fmt.Fprintf(functions, "\nfunc Copy%s(src *%s.%s) *%[1]s {\n", typ, key[0], event)
+			fmt.Fprintf(functions, "\nfunc Copy%s(src *%s.%s) *%[1]s {\n", typ, src, event)
 			fmt.Fprintf(functions, "  b := %s_builder {\n", typ)
 
 			prevMessage = f.key
@@ -9,11 +9,12 @@ import (
 	"strings"
 
 	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
 )
 
 // Measure captures system's network interface metrics.
 func Measure() (ms []message.Content) {
-	f, err := os.Open("/proc/net/dev")
+	f, err := os.Open(sysroot.Proc("net", "dev"))
 	if err != nil {
 		return
 	}
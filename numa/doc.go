@@ -0,0 +1,12 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package numa measures the memory and the allocation hit and miss counts of each NUMA node for
+the "gomon" command.
+
+The placement of a watched process' memory across the NUMA nodes is reported by the process
+measurement (see the -watch flag).
+
+NUMA nodes are reported for Linux only.
+*/
+package numa
@@ -0,0 +1,12 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package numa
+
+import (
+	"github.com/zosmac/gomon/message"
+)
+
+// Measure captures the NUMA nodes' metrics. Not reported on this platform.
+func Measure() []message.Content {
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package numa
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+// Measure captures the NUMA nodes' memory and allocation metrics.
+func Measure() (ms []message.Content) {
+	nodes, _ := filepath.Glob(sysroot.Sys("devices", "system", "node", "node[0-9]*"))
+	for _, dirname := range nodes {
+		meminfo := values(filepath.Join(dirname, "meminfo"))
+		numastat := values(filepath.Join(dirname, "numastat"))
+		cpus, _ := os.ReadFile(filepath.Join(dirname, "cpulist"))
+
+		ms = append(ms, &Measurement{
+			Header: message.Measurement(),
+			EventID: EventID{
+				Node: filepath.Base(dirname),
+			},
+			Properties: Properties{
+				Cpus: strings.TrimSpace(string(cpus)),
+			},
+			Metrics: Metrics{
+				Memory: Memory{
+					Total:     meminfo["MemTotal"] * 1024, // kB
+					Free:      meminfo["MemFree"] * 1024,
+					Used:      meminfo["MemUsed"] * 1024,
+					FilePages: meminfo["FilePages"] * 1024,
+					AnonPages: meminfo["AnonPages"] * 1024,
+					Shmem:     meminfo["Shmem"] * 1024,
+					Slab:      meminfo["Slab"] * 1024,
+				},
+				Stats: Stats{
+					Hit:           numastat["numa_hit"],
+					Miss:          numastat["numa_miss"],
+					Foreign:       numastat["numa_foreign"],
+					InterleaveHit: numastat["interleave_hit"],
+					LocalNode:     numastat["local_node"],
+					OtherNode:     numastat["other_node"],
+				},
+			},
+		})
+	}
+
+	return
+}
+
+// values reads the key value pairs of a node's meminfo (e.g. "Node 0 MemFree: 3301952 kB") or numastat (e.g. "numa_hit 15148896").
+func values(filename string) map[string]int {
+	m := map[string]int{}
+	f, err := os.Open(filename)
+	if err != nil {
+		gocore.Error(filename, err).Err()
+		return m
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) >= 4 && fields[0] == "Node" { // meminfo
+			m[strings.TrimSuffix(fields[2], ":")], _ = strconv.Atoi(fields[3])
+		} else if len(fields) == 2 {
+			m[fields[0]], _ = strconv.Atoi(fields[1])
+		}
+	}
+
+	return m
+}
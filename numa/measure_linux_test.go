@@ -0,0 +1,95 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package numa
+
+import (
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("sysfs", "testdata/sys")
+	os.Exit(m.Run())
+}
+
+func TestValues(t *testing.T) {
+	want := map[string]int{
+		"MemTotal":        16315372,
+		"MemFree":         3301952,
+		"MemUsed":         13013420,
+		"Active":          6053608,
+		"FilePages":       8123456,
+		"AnonPages":       2345678,
+		"Shmem":           98765,
+		"Slab":            654321,
+		"HugePages_Total": 0,
+		"HugePages_Free":  0,
+	}
+	if got := values("testdata/sys/devices/system/node/node0/meminfo"); !maps.Equal(got, want) {
+		t.Errorf("values() meminfo = %v, want %v", got, want)
+	}
+
+	want = map[string]int{
+		"numa_hit":       15148896,
+		"numa_miss":      0,
+		"numa_foreign":   1200,
+		"interleave_hit": 33251,
+		"local_node":     15102543,
+		"other_node":     46353,
+	}
+	if got := values("testdata/sys/devices/system/node/node0/numastat"); !maps.Equal(got, want) {
+		t.Errorf("values() numastat = %v, want %v", got, want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	want := []Measurement{
+		{
+			EventID:    EventID{Node: "node0"},
+			Properties: Properties{Cpus: "0-7,16-23"},
+			Metrics: Metrics{
+				Memory: Memory{
+					Total:     16315372 * 1024,
+					Free:      3301952 * 1024,
+					Used:      13013420 * 1024,
+					FilePages: 8123456 * 1024,
+					AnonPages: 2345678 * 1024,
+					Shmem:     98765 * 1024,
+					Slab:      654321 * 1024,
+				},
+				Stats: Stats{Hit: 15148896, Foreign: 1200, InterleaveHit: 33251, LocalNode: 15102543, OtherNode: 46353},
+			},
+		},
+		{
+			EventID:    EventID{Node: "node1"},
+			Properties: Properties{Cpus: "8-15,24-31"},
+			Metrics: Metrics{
+				Memory: Memory{
+					Total:     16515072 * 1024,
+					Free:      9000000 * 1024,
+					Used:      7515072 * 1024,
+					FilePages: 4000000 * 1024,
+					AnonPages: 3000000 * 1024,
+					Shmem:     10000 * 1024,
+					Slab:      300000 * 1024,
+				},
+				Stats: Stats{Hit: 9876543, Miss: 1200, InterleaveHit: 33180, LocalNode: 9800000, OtherNode: 76543},
+			},
+		},
+	}
+
+	ms := Measure()
+	if len(ms) != len(want) {
+		t.Fatalf("Measure() = %d measurements, want %d", len(ms), len(want))
+	}
+	for i, c := range ms {
+		m := c.(*Measurement)
+		if m.EventID != want[i].EventID || m.Properties != want[i].Properties || m.Metrics != want[i].Metrics {
+			t.Errorf("Measure() %s = %+v %+v, want %+v %+v",
+				m.Node, m.Properties, m.Metrics, want[i].Properties, want[i].Metrics)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package numa
+
+import (
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Node string `json:"node" gomon:"property"`
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		Cpus string `json:"cpus" gomon:"property"` // list of the node's CPUs, e.g. 0-7,16-23
+	}
+
+	// Memory contains a node's memory metrics.
+	Memory struct {
+		Total     int `json:"total" gomon:"gauge,B"`
+		Free      int `json:"free" gomon:"gauge,B"`
+		Used      int `json:"used" gomon:"gauge,B"`
+		FilePages int `json:"file_pages" gomon:"gauge,B"`
+		AnonPages int `json:"anon_pages" gomon:"gauge,B"`
+		Shmem     int `json:"shmem" gomon:"gauge,B"`
+		Slab      int `json:"slab" gomon:"gauge,B"`
+	}
+
+	// Stats contains a node's page allocation counts.
+	Stats struct {
+		Hit           int `json:"hit" gomon:"counter,count"`     // allocated on this node as intended
+		Miss          int `json:"miss" gomon:"counter,count"`    // allocated on this node instead of the intended node
+		Foreign       int `json:"foreign" gomon:"counter,count"` // intended for this node but allocated on another
+		InterleaveHit int `json:"interleave_hit" gomon:"counter,count"`
+		LocalNode     int `json:"local_node" gomon:"counter,count"` // allocated on this node for a process running on it
+		OtherNode     int `json:"other_node" gomon:"counter,count"` // allocated on this node for a process running on another
+	}
+
+	// Metrics defines measurement metrics.
+	Metrics struct {
+		Memory Memory `json:"memory" gomon:""`
+		Stats  Stats  `json:"stats" gomon:""`
+	}
+
+	// Measurement defines the properties and metrics of a NUMA node measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+		Metrics                              `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a NUMA node message.
+func (m *Measurement) ID() string {
+	return m.EventID.Node
+}
@@ -15,8 +15,17 @@ var (
 	// endpoints of processes periodically populated by lsof on unix.
 	epMap  = map[Pid][]Connection{}
 	epLock sync.RWMutex
+
+	// fdMap tallies the open file descriptors of processes, populated with epMap and guarded by epLock.
+	fdMap = map[Pid]*fdTally{}
 )
 
+// fdTally counts a process' open file descriptors by type and lists the inodes of its TCP and UDP sockets.
+type fdTally struct {
+	Fds
+	sockets []uint32
+}
+
 // connections determines the remote endpoints of each process' connections.
 func connections(epm map[Pid][]Connection) {
 	defer func() {
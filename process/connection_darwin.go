@@ -15,3 +15,8 @@ func lsofCommand() []string {
 	sample, _ := time.ParseDuration(gocore.Flags.Lookup("sample").Value.String())
 	return strings.Fields(fmt.Sprintf("lsof +c0 -l -n -P -X -r%dm====%%T====", sample/time.Second))
 }
+
+// listenQueues not reported on this platform.
+func listenQueues() map[string][2]int {
+	return nil
+}
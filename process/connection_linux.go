@@ -15,3 +15,12 @@ func lsofCommand() []string {
 	sample, _ := time.ParseDuration(gocore.Flags.Lookup("sample").Value.String())
 	return strings.Fields(fmt.Sprintf("lsof +E -Ki -l -n -P -d ^cwd,^mem,^rtd,^txt,^DEL -r%dm====%%T====", sample/time.Second))
 }
+
+// listenQueues reports the accept queue depth and backlog of listening sockets.
+func listenQueues() map[string][2]int {
+	queues, err := nlListenQueues()
+	if err != nil {
+		gocore.Error("listenQueues", err).Warn()
+	}
+	return queues
+}
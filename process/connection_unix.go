@@ -11,16 +11,22 @@ import (
 	"math"
 	"net"
 	"os"
+	"os/exec"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/capability"
 	"github.com/zosmac/gomon/logs"
 )
 
 var (
+	// lsofSpawned and lsofRoot record whether lsof was started, and with root authority.
+	lsofSpawned, lsofRoot atomic.Bool
+
 	// headerRegex for parsing lsof header line of lsof command.
 	headerRegex = regexp.MustCompile(
 		`^(?P<command>COMMAND) ` +
@@ -127,12 +133,46 @@ func Endpoints(ctx context.Context) error {
 			"command": "lsof",
 		})
 	}
+	lsofSpawned.Store(true)
+	lsofRoot.Store(os.Geteuid() == 0)
 
 	go parseLsof(stdout)
 
 	return nil
 }
 
+// Open opens the privileged resources of the process measurements and observations before gomon
+// relinquishes root authority. If endpoints is set and lsof is available, it starts lsof to report
+// process connections. If events is set, it opens the process event source for the Observer.
+func Open(ctx context.Context, endpoints, events bool) error {
+	if _, err := exec.LookPath("lsof"); endpoints && err == nil {
+		if err := Endpoints(ctx); err != nil {
+			return err
+		}
+	}
+	if events {
+		openEvents()
+	}
+	openPrivileged()
+	return nil
+}
+
+// lsofProbe determines whether lsof is available to report process connections.
+func lsofProbe() capability.Capability {
+	c := capability.Capability{Name: "lsof", Mode: capability.Full}
+	if _, err := exec.LookPath("lsof"); err != nil {
+		c.Mode = capability.Disabled
+		c.Detail = "process connections unreported"
+		c.Measurements = []string{"listeners"}
+	} else if lsofSpawned.Load() && lsofRoot.Load() {
+		// lsof retains the root authority with which it was started
+	} else if os.Geteuid() != 0 {
+		c.Mode = capability.Degraded
+		c.Detail = "connections of other users' processes unreported"
+	}
+	return c
+}
+
 // parseLsof parses each line of stdout from the command.
 func parseLsof(sc *bufio.Scanner) {
 	defer func() {
@@ -147,6 +187,8 @@ func parseLsof(sc *bufio.Scanner) {
 	}()
 
 	epm := map[Pid][]Connection{}
+	fdm := map[Pid]*fdTally{}
+	lsm := map[string]listener{}
 	var indexUser, indexFd, indexMode /* indexLock, */, indexType, indexDevice, indexSize, indexNode, indexName int
 
 	for sc.Scan() {
@@ -174,8 +216,16 @@ func parseLsof(sc *bufio.Scanner) {
 			connections(epm) // resolve inter process connections
 			epLock.Lock()
 			epMap = epm
+			fdMap = fdm
 			epLock.Unlock()
 			epm = map[Pid][]Connection{}
+			fdm = map[Pid]*fdTally{}
+			lsLock.Lock()
+			prev := lsMap
+			lsMap = lsm
+			lsLock.Unlock()
+			listenChanges(prev, lsm)
+			lsm = map[string]listener{}
 			continue
 		}
 
@@ -184,9 +234,9 @@ func parseLsof(sc *bufio.Scanner) {
 			continue
 		}
 
-		// command := strings.Fields(text[:indexUser])[0]           // COMMAND and PID fields may be jammed together
+		command := strings.Fields(text[:indexUser])[0]              // COMMAND and PID fields may be jammed together
 		pid, _ := strconv.Atoi(strings.Fields(text[:indexUser])[1]) // so read as one field and split
-		// user := strings.TrimSpace(text[indexUser:indexFd])
+		user := strings.TrimSpace(text[indexUser:indexFd])
 		mode := text[indexMode]
 		// lock := text[indexLock]
 		fdType := strings.TrimSpace(text[indexType:indexDevice])
@@ -195,7 +245,9 @@ func parseLsof(sc *bufio.Scanner) {
 		node := strings.TrimSpace(text[indexNode:indexName])
 		name := text[indexName:]
 
-		var self, peer string
+		tally(fdm, Pid(pid), fdType, device)
+
+		var self, peer, class string
 		var peerPid Pid
 		var ok bool
 
@@ -266,6 +318,14 @@ func parseLsof(sc *bufio.Scanner) {
 				if peer = matches[nameGroups[groupInode]]; len(peer) == 0 {
 					peer = matches[nameGroups[groupName]]
 				}
+				if matches[nameGroups[groupState]] == "LISTEN" {
+					addListener(lsm, listener{
+						ListenerID: ListenerID{Protocol: fdType, Address: peer, Inode: self},
+						pid:        Pid(pid),
+						command:    command,
+						user:       user,
+					})
+				}
 				pid, _ := strconv.Atoi(matches[nameGroups[groupPid]])
 				peerPid = Pid(pid)
 			}
@@ -279,9 +339,18 @@ func parseLsof(sc *bufio.Scanner) {
 			if len(split) > 1 {
 				self = addZone(split[0])
 				peer = addZone(split[1])
+				class = Classify(peer)
 			} else { // listen
 				self = device
 				peer = addZone(split[0])
+				if node == "TCP" && strings.HasSuffix(name, "(LISTEN)") || node == "UDP" {
+					addListener(lsm, listener{
+						ListenerID: ListenerID{Protocol: node, Address: peer, Inode: device},
+						pid:        Pid(pid),
+						command:    command,
+						user:       user,
+					})
+				}
 			}
 			if _, _, err := net.SplitHostPort(peer); err == nil { // host connection
 				var ok bool
@@ -300,9 +369,10 @@ func parseLsof(sc *bufio.Scanner) {
 		if name != os.DevNull {
 			epm[Pid(pid)] = append(epm[Pid(pid)],
 				Connection{
-					Type: fdType,
-					Self: Endpoint{Name: self, Pid: Pid(pid)},
-					Peer: Endpoint{Name: peer, Pid: peerPid},
+					Type:  fdType,
+					Self:  Endpoint{Name: self, Pid: Pid(pid)},
+					Peer:  Endpoint{Name: peer, Pid: peerPid},
+					Class: class,
 				},
 			)
 		}
@@ -323,6 +393,31 @@ func parseLsof(sc *bufio.Scanner) {
 	}
 }
 
+// tally counts an open file descriptor of a process by its lsof TYPE.
+func tally(fdm map[Pid]*fdTally, pid Pid, fdType, device string) {
+	t, ok := fdm[pid]
+	if !ok {
+		t = &fdTally{}
+		fdm[pid] = t
+	}
+	t.FdCount++
+	switch fdType {
+	case "IPv4", "IPv6":
+		if inode, err := strconv.ParseUint(device, 10, 32); err == nil { // on linux, DEVICE is the socket's inode
+			t.sockets = append(t.sockets, uint32(inode))
+		}
+		fallthrough
+	case "unix", "sock", "netlink", "raw", "raw6", "pack", "systm", "ndrv":
+		t.FdSockets++
+	case "FIFO", "PIPE":
+		t.FdPipes++
+	case "a_inode":
+		t.FdAnonInodes++
+	default:
+		t.FdFiles++
+	}
+}
+
 func addZone(addr string) string {
 	ip, port, _ := net.SplitHostPort(addr)
 	match := zoneregex.FindStringSubmatch(ip)
@@ -12,3 +12,13 @@ import (
 func Endpoints(_ context.Context) error {
 	return gocore.Unsupported()
 }
+
+// Open opens the privileged resources of the process measurements and observations. None on this platform.
+func Open(_ context.Context, _, _ bool) error {
+	return nil
+}
+
+// listenQueues not reported on this platform.
+func listenQueues() map[string][2]int {
+	return nil
+}
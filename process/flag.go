@@ -3,18 +3,74 @@
 package process
 
 import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
 	"github.com/zosmac/gocore"
 )
 
 var (
 	// flags defines the command line flags.
 	flags = struct {
-		top uint
+		top         uint
+		watch       gocore.Regexp
+		mappings    uint
+		fdThreshold float64
+		redactKeys  gocore.Regexp
+		redact      gocore.Regexp
+		subnets     subnets
+		dnsTTL      time.Duration
+		ancestry    bool
 	}{
-		top: 5,
+		top:         5,
+		mappings:    0,
+		fdThreshold: 90.0,
+		dnsTTL:      10 * time.Minute,
+	}
+)
+
+type (
+	// subnet labels a network address range.
+	subnet struct {
+		label  string
+		prefix netip.Prefix
 	}
+
+	// subnets is a command line flag type.
+	subnets []subnet
 )
 
+// Set is a flag.Value interface method to enable subnets as a command line flag.
+func (ss *subnets) Set(s string) error {
+	*ss = nil
+	for _, s := range strings.Split(s, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		label, cidr, ok := strings.Cut(s, "=")
+		if !ok || label == "" {
+			return gocore.Error("subnet", fmt.Errorf("%q not in label=cidr form", s))
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return gocore.Error("ParsePrefix", err)
+		}
+		*ss = append(*ss, subnet{label: label, prefix: prefix.Masked()})
+	}
+	return nil
+}
+
+// String is a flag.Value interface method to enable subnets as a command line flag.
+func (ss subnets) String() string {
+	s := make([]string, len(ss))
+	for i, sn := range ss {
+		s[i] = sn.label + "=" + sn.prefix.String()
+	}
+	return strings.Join(s, ",")
+}
+
 // init initializes the command line flags.
 func init() {
 	gocore.Flags.Var(
@@ -23,4 +79,60 @@ func init() {
 		"[-top <count>]",
 		"The `count` to report of processes consuming most CPU time",
 	)
+	gocore.Flags.Var(
+		&flags.watch,
+		"watch",
+		"[-watch <expression>]",
+		"A regular `expression` matching names of processes to measure in detail (e.g. per thread)",
+	)
+	gocore.Flags.Var(
+		&flags.mappings,
+		"mappings",
+		"[-mappings <count>]",
+		"The `count` to report of a watched process' memory mappings with the largest proportional set size (linux only)",
+	)
+	gocore.Flags.Var(
+		&flags.fdThreshold,
+		"fdthreshold",
+		"[-fdthreshold <percent>]",
+		"The `percent` of its open file descriptor limit above which to report and warn of a process (linux only)",
+	)
+
+	flags.redactKeys.Set(`(?i)passw(or)?d|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credential`)
+	gocore.Flags.Var(
+		&flags.redactKeys,
+		"redactkeys",
+		"[-redactkeys <expression>]",
+		"A regular `expression` matching names of environment variables and command line options whose values to redact when captured, for every sink. Specify '' to disable",
+	)
+	flags.redact.Set(`://[^/:@\s]+:([^/@\s]+)@`)
+	gocore.Flags.Var(
+		&flags.redact,
+		"redact",
+		"[-redact <expression>]",
+		"A regular `expression` matching secrets in command line arguments, environment values and nodegraph endpoints to redact, or if it has a subexpression, the secret within. Specify '' to disable",
+	)
+	gocore.Flags.Var(
+		&flags.subnets,
+		"subnets",
+		"[-subnets <label>=<cidr>,...]",
+		"A comma-separated list of `subnets` with labels to classify remote peers of process connections (e.g. prod-db-subnet=10.1.2.0/24)",
+	)
+	gocore.Flags.Var(
+		&flags.dnsTTL,
+		"dnsttl",
+		"[-dnsttl <interval>]",
+		"The `interval` for which to cache the reverse-DNS name of a remote host",
+	)
+	gocore.Flags.Var(
+		&flags.ancestry,
+		"ancestry",
+		"[-ancestry]",
+		"Report the chain of ancestor process names of each process measured",
+	)
+}
+
+// watched reports whether a process name is selected by the watch flag for detailed measurement.
+func watched(name string) bool {
+	return flags.watch.Regexp != nil && flags.watch.MatchString(name)
 }
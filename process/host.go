@@ -0,0 +1,145 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// hostEntry caches the reverse-DNS name of a remote host.
+	hostEntry struct {
+		name    string
+		expires time.Time
+	}
+)
+
+const (
+	// remote host classes.
+	classLoopback  = "loopback"
+	classPrivate   = "private"
+	classLinkLocal = "link-local"
+	classMulticast = "multicast"
+	classPublic    = "public"
+
+	// maxLookups bounds the reverse-DNS lookups in flight.
+	maxLookups = 8
+)
+
+var (
+	// hosts caches reverse-DNS names of remote host addresses.
+	hosts     = map[string]*hostEntry{}
+	hostSweep time.Time // when expired entries were last evicted
+	hostLock  sync.Mutex
+
+	// lookups holds a slot for each reverse-DNS lookup in flight.
+	lookups = make(chan struct{}, maxLookups)
+
+	// lookupAddr performs the reverse-DNS lookup of an address.
+	lookupAddr = net.LookupAddr
+)
+
+// Hostname returns the cached reverse-DNS name of a host address, or the address until a lookup completes.
+// Lookups run asynchronously, at most maxLookups at a time, and are repeated after the cached name expires.
+// A lookup not started for want of a slot is tried at the next request. Names not requested since they
+// expired are evicted.
+func Hostname(addr string) string {
+	ip := strings.Split(addr, "%")[0] // strip zone
+	now := time.Now()
+
+	hostLock.Lock()
+	defer hostLock.Unlock()
+	if now.After(hostSweep.Add(flags.dnsTTL)) {
+		hostSweep = now
+		for ip, h := range hosts {
+			if now.After(h.expires.Add(flags.dnsTTL)) {
+				delete(hosts, ip)
+			}
+		}
+	}
+	h, ok := hosts[ip]
+	if !ok {
+		h = &hostEntry{name: ip}
+		hosts[ip] = h
+	}
+	if now.After(h.expires) {
+		select {
+		case lookups <- struct{}{}:
+			h.expires = now.Add(flags.dnsTTL) // suppress concurrent lookups
+			go func() {
+				defer func() { <-lookups }()
+				name := ip
+				if ns, err := lookupAddr(ip); err == nil && len(ns) > 0 {
+					name = strings.TrimSuffix(ns[0], ".")
+				}
+				hostLock.Lock()
+				h.name = name
+				h.expires = time.Now().Add(flags.dnsTTL)
+				hostLock.Unlock()
+			}()
+		default:
+		}
+	}
+
+	return h.name
+}
+
+// Classify labels a remote endpoint address as loopback, private, link-local, multicast or public,
+// or with the label of the first user-specified subnet that contains it.
+func Classify(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip, err := netip.ParseAddr(strings.Split(host, "%")[0])
+	if err != nil {
+		return ""
+	}
+	ip = ip.Unmap()
+
+	for _, s := range flags.subnets {
+		if s.prefix.Contains(ip) {
+			return s.label
+		}
+	}
+
+	switch {
+	case ip.IsLoopback():
+		return classLoopback
+	case ip.IsPrivate():
+		return classPrivate
+	case ip.IsLinkLocalUnicast():
+		return classLinkLocal
+	case ip.IsMulticast():
+		return classMulticast
+	}
+	return classPublic
+}
+
+// remotes counts a process' distinct remote hosts by class.
+func remotes(conns []Connection) map[string]int {
+	var rm map[string]int
+	peers := map[string]struct{}{}
+	for _, conn := range conns {
+		if conn.Peer.Pid >= 0 || conn.Class == "" {
+			continue // not a remote host
+		}
+		host, _, err := net.SplitHostPort(conn.Peer.Name)
+		if err != nil {
+			host = conn.Peer.Name
+		}
+		if _, ok := peers[host]; ok {
+			continue
+		}
+		peers[host] = struct{}{}
+		if rm == nil {
+			rm = map[string]int{}
+		}
+		rm[conn.Class]++
+	}
+	return rm
+}
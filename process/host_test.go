@@ -0,0 +1,127 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubnets(t *testing.T) {
+	var ss subnets
+	if err := ss.Set(" prod-db=10.1.2.7/24, ,office=2001:db8::/32"); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	if want := "prod-db=10.1.2.0/24,office=2001:db8::/32"; ss.String() != want {
+		t.Errorf("Set() = %q, want %q", ss.String(), want)
+	}
+
+	for _, s := range []string{"10.1.2.0/24", "=10.1.2.0/24", "prod-db=10.1.2.0", "prod-db=10.1.2.0/33"} {
+		if err := ss.Set(s); err == nil {
+			t.Errorf("Set(%q) accepted", s)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	saved := flags.subnets
+	defer func() { flags.subnets = saved }()
+	if err := flags.subnets.Set("prod-db=10.1.2.0/24,office=2001:db8::/32"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr, want string
+	}{
+		{"127.0.0.1:8080", classLoopback},
+		{"[::1]:443", classLoopback},
+		{"10.1.2.3:5432", "prod-db"},          // user subnet preferred to private
+		{"[::ffff:10.1.2.3]:5432", "prod-db"}, // IPv4-mapped
+		{"10.9.8.7:22", classPrivate},
+		{"192.168.1.1", classPrivate},
+		{"[fd00::1]:53", classPrivate},
+		{"169.254.169.254:80", classLinkLocal},
+		{"[fe80::1%eth0]:546", classLinkLocal},
+		{"224.0.0.251:5353", classMulticast},
+		{"[2001:db8::1]:443", "office"},
+		{"8.8.8.8:53", classPublic},
+		{"not an address", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(tt.addr); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestRemotes(t *testing.T) {
+	conns := []Connection{
+		{Type: "TCP", Peer: Endpoint{Name: "8.8.8.8:53", Pid: -1}, Class: classPublic},
+		{Type: "TCP", Peer: Endpoint{Name: "8.8.8.8:443", Pid: -1}, Class: classPublic}, // same host
+		{Type: "TCP", Peer: Endpoint{Name: "10.1.2.3:5432", Pid: -1}, Class: "prod-db"},
+		{Type: "TCP", Peer: Endpoint{Name: "[2001:db8::1]:443", Pid: -1}, Class: classPublic},
+		{Type: "TCP", Peer: Endpoint{Name: "127.0.0.1:8080", Pid: 1234}, Class: classLoopback}, // local process
+		{Type: "unix", Peer: Endpoint{Name: "/run/systemd/journal/stdout", Pid: -1}},           // not a host
+	}
+	want := map[string]int{classPublic: 2, "prod-db": 1}
+	if got := remotes(conns); !maps.Equal(got, want) {
+		t.Errorf("remotes() = %v, want %v", got, want)
+	}
+	if got := remotes(conns[4:]); got != nil {
+		t.Errorf("remotes() of local connections = %v, want nil", got)
+	}
+}
+
+func TestHostname(t *testing.T) {
+	var inflight, peak atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func(f func(string) ([]string, error)) { lookupAddr = f }(lookupAddr)
+	lookupAddr = func(addr string) ([]string, error) {
+		defer wg.Done()
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		<-release
+		return []string{"host-" + addr + ".example."}, nil
+	}
+
+	wg.Add(maxLookups)
+	for i := range 2 * maxLookups {
+		ip := fmt.Sprintf("192.0.2.%d", i)
+		if got := Hostname(ip); got != ip {
+			t.Errorf("Hostname(%q) = %q before lookup", ip, got)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if p := peak.Load(); p > maxLookups {
+		t.Errorf("Hostname() ran %d lookups at once, limit %d", p, maxLookups)
+	}
+
+	// the slots are released as lookups complete
+	for len(lookups) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := Hostname("192.0.2.0"), "host-192.0.2.0.example"; got != want {
+		t.Errorf("Hostname() = %q, want %q", got, want)
+	}
+
+	// the addresses refused a slot are looked up when next requested
+	ip := fmt.Sprintf("192.0.2.%d", maxLookups)
+	wg.Add(1)
+	Hostname(ip)
+	wg.Wait()
+	for len(lookups) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := Hostname(ip), "host-"+ip+".example"; got != want {
+		t.Errorf("Hostname() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Listener{})
+	message.Define(&ListenerObservation{})
+}
+
+type (
+	// listenEvent type.
+	listenEvent string
+
+	// ListenerID identifies a listening socket.
+	ListenerID struct {
+		Protocol string `json:"protocol" gomon:"property"`
+		Address  string `json:"address" gomon:"property"`
+		Inode    string `json:"inode" gomon:"property"`
+	}
+
+	// ListenerProperties defines the properties of a listening socket's owning process.
+	ListenerProperties struct {
+		Executable string `json:"executable,omitempty" gomon:"property"`
+		Username   string `json:"username,omitempty" gomon:"property"`
+	}
+
+	// Listener defines the properties and metrics of a listening socket measurement.
+	Listener struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		ListenerID                           `json:"listener_id" gomon:""`
+		EventID                              `json:"event_id" gomon:""` // of the owning process
+		ListenerProperties                   `gomon:""`
+		Backlog                              int `json:"backlog,omitempty" gomon:"gauge,count,linux"`
+		AcceptQueue                          int `json:"accept_queue,omitempty" gomon:"gauge,count,linux"`
+	}
+
+	// ListenerObservation defines the properties of a listening socket opened or closed observation.
+	ListenerObservation struct {
+		message.Header[listenEvent] `gomon:""`
+		ListenerID                  `json:"listener_id" gomon:""`
+		EventID                     `json:"event_id" gomon:""` // of the owning process
+		ListenerProperties          `gomon:""`
+	}
+
+	// listener records a listening socket reported by lsof.
+	listener struct {
+		ListenerID
+		pid     Pid
+		command string
+		user    string
+	}
+)
+
+const (
+	// message events.
+	listenOpened listenEvent = "opened"
+	listenClosed listenEvent = "closed"
+)
+
+var (
+	// listenEvents valid event values for messages.
+	listenEvents = gocore.ValidValue[listenEvent]{}.Define(
+		listenOpened,
+		listenClosed,
+	)
+
+	// listening sockets of processes periodically populated by lsof on unix, keyed by inode.
+	lsMap  map[string]listener
+	lsLock sync.RWMutex
+
+	// listening indicates that listener measurements are requested, enabling change observations.
+	listening atomic.Bool
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Listener) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a listener message.
+func (m *Listener) ID() string {
+	return m.Protocol + " " + m.Address + " " + m.EventID.Name + "[" + m.EventID.Pid.String() + "]"
+}
+
+// Events returns the list of acceptable Event values for this message.
+func (*ListenerObservation) Events() []string {
+	return listenEvents.ValidValues()
+}
+
+// ID returns the identifier for a listener observation message.
+func (obs *ListenerObservation) ID() string {
+	return obs.Protocol + " " + obs.Address + " " + obs.EventID.Name + "[" + obs.EventID.Pid.String() + "]"
+}
+
+// Listeners captures the listening sockets of processes.
+func Listeners() []message.Content {
+	listening.Store(true)
+	lsLock.RLock()
+	lsm := lsMap
+	lsLock.RUnlock()
+
+	queues := listenQueues()
+	ms := make([]message.Content, 0, len(lsm))
+	for inode, l := range lsm {
+		id, props := l.owner()
+		m := &Listener{
+			Header:             message.Measurement(),
+			ListenerID:         l.ListenerID,
+			EventID:            id,
+			ListenerProperties: props,
+		}
+		if q, ok := queues[inode]; ok {
+			m.AcceptQueue, m.Backlog = q[0], q[1]
+		}
+		ms = append(ms, m)
+	}
+
+	slices.SortFunc(ms, func(a, b message.Content) int {
+		return cmp.Compare(a.ID(), b.ID())
+	})
+
+	return ms
+}
+
+// listenChanges reports the listening sockets opened and closed since lsof's previous report.
+func listenChanges(prev, curr map[string]listener) {
+	if prev == nil || !listening.Load() {
+		return // no baseline, or listeners not measured
+	}
+	var obs []message.Content
+	for inode, l := range curr {
+		if _, ok := prev[inode]; !ok {
+			obs = append(obs, l.observation(listenOpened))
+		}
+	}
+	for inode, l := range prev {
+		if _, ok := curr[inode]; !ok {
+			obs = append(obs, l.observation(listenClosed))
+		}
+	}
+	if len(obs) > 0 {
+		message.Observations(obs)
+	}
+}
+
+// observation formats a listener observation message for an event.
+func (l listener) observation(ev listenEvent) *ListenerObservation {
+	id, props := l.owner()
+	return &ListenerObservation{
+		Header:             message.Observation(time.Now(), ev),
+		ListenerID:         l.ListenerID,
+		EventID:            id,
+		ListenerProperties: props,
+	}
+}
+
+// owner identifies the process that owns a listening socket.
+func (l listener) owner() (EventID, ListenerProperties) {
+	id := Identify(l.pid)
+	if id.Name == "" {
+		id.Name = l.command
+	}
+	props := ListenerProperties{Username: l.user}
+	if uid, err := strconv.Atoi(l.user); err == nil { // lsof -l reports numeric uid
+		props.Username = gocore.Username(uid)
+	}
+	procLock.RLock()
+	if p, ok := procs[l.pid]; ok {
+		props.Executable = p.Executable
+	}
+	procLock.RUnlock()
+	return id, props
+}
+
+// addListener records a listening socket, attributing a socket shared by several processes to the lowest pid.
+func addListener(lsm map[string]listener, l listener) {
+	if ll, ok := lsm[l.Inode]; !ok || l.pid < ll.pid {
+		lsm[l.Inode] = l
+	}
+}
@@ -3,7 +3,9 @@
 package process
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"sync"
@@ -34,6 +36,9 @@ var (
 	// prevProcs and pms used to report only processes that are currently and haveconsumed CPU since the previous measurement.
 	prevProcs = Table{}
 	pms       = []message.Content{}
+
+	// fdWarned records the start times of processes warned of nearing their open file descriptor limit.
+	fdWarned = map[Pid]time.Time{}
 )
 
 // Measure captures all processes' metrics.
@@ -58,8 +63,9 @@ func Measure() (ProcStats, []message.Content) {
 
 	var active, execed int
 	var total time.Duration
+	var appeared []*Process
 	for pid, p := range tb {
-		if pp, ok := ptb[pid]; ok {
+		if pp, ok := previous(ptb, p); ok {
 			if diff := p.Total - pp.Total; diff > 0 {
 				diffCPU[pid] = diff
 				cpus = append(cpus, diff)
@@ -73,6 +79,17 @@ func Measure() (ProcStats, []message.Content) {
 			active++
 			execed++
 			total += p.Total
+			appeared = append(appeared, p) // a new process, or a pid reused by one
+		}
+	}
+
+	// without process events, observe the differences between process table snapshots
+	if fallback.Load() {
+		for _, p := range appeared {
+			p.appeared()
+		}
+		for pid := range exited {
+			ptb[pid].disappeared()
 		}
 	}
 
@@ -95,20 +112,102 @@ func Measure() (ProcStats, []message.Content) {
 		tops[m.(*Measurement).EventID.Pid] = struct{}{}
 	}
 	for _, m := range pms {
-		pid := m.(*Measurement).EventID.Pid
+		pm := m.(*Measurement)
+		pid := pm.EventID.Pid
 		if _, ok := tops[pid]; ok {
 			continue
 		}
-		if p, ok := tb[pid]; ok {
-			if pp, ok := ptb[pid]; ok {
+		if p, ok := tb[pid]; ok && p.Starttime.Equal(pm.Starttime) {
+			if pp, ok := previous(ptb, p); ok {
 				if diff := p.Total - pp.Total; diff > 0 {
-					ms = append(ms, m)
+					ms = append(ms, p)
+					tops[pid] = struct{}{}
 				}
 			}
 		}
 	}
+
+	// report processes moving the most network traffic since the previous measurement
+	diffNet := map[Pid]int{}
+	for pid, p := range tb {
+		diff := p.NetworkSent + p.NetworkReceived
+		if pp, ok := previous(ptb, p); ok {
+			diff -= pp.NetworkSent + pp.NetworkReceived
+		}
+		if diff > 0 {
+			diffNet[pid] = diff
+		}
+	}
+	nets := slices.SortedFunc(maps.Keys(diffNet), func(a, b Pid) int {
+		return cmp.Compare(diffNet[b], diffNet[a])
+	})
+	for _, pid := range nets[:min(len(nets), int(flags.top))] {
+		if _, ok := tops[pid]; !ok {
+			ms = append(ms, tb[pid])
+			tops[pid] = struct{}{}
+		}
+	}
+
+	// report processes nearing their open file descriptor limit, warning as they cross the threshold
+	for pid, start := range fdWarned {
+		if p, ok := tb[pid]; !ok || !p.Starttime.Equal(start) {
+			delete(fdWarned, pid)
+		}
+	}
+	for pid, p := range tb {
+		_, warned := fdWarned[pid]
+		if p.FdLimit == 0 || p.FdPercent < flags.fdThreshold {
+			if warned {
+				delete(fdWarned, pid)
+				gocore.Error("fd limit", nil, map[string]string{
+					"process": p.Shortname(),
+					"count":   strconv.Itoa(p.FdCount),
+					"limit":   strconv.Itoa(p.FdLimit),
+				}).Info()
+			}
+			continue
+		}
+		if !warned {
+			fdWarned[pid] = p.Starttime
+			gocore.Error("fd limit", fmt.Errorf("%.1f%% of open file descriptor limit", p.FdPercent), map[string]string{
+				"process": p.Shortname(),
+				"count":   strconv.Itoa(p.FdCount),
+				"limit":   strconv.Itoa(p.FdLimit),
+			}).Warn()
+		}
+		if _, ok := tops[pid]; !ok {
+			ms = append(ms, p)
+			tops[pid] = struct{}{}
+		}
+	}
+
+	// report watched processes regardless of their CPU consumption, and their threads
+	var ts []message.Content
+	for pid, p := range tb {
+		if !watched(p.EventID.Name) {
+			continue
+		}
+		if _, ok := tops[pid]; !ok {
+			ms = append(ms, p)
+		}
+		ts = append(ts, p.tasks()...)
+	}
 	pms = ms
 
+	// query the delays and optionally the ancestry of the processes being reported
+	var tr Tree
+	if flags.ancestry {
+		tr = tb.BuildTree()
+	}
+	for _, m := range ms {
+		p := m.(*Measurement)
+		p.Delays = p.Pid.delays()
+		if flags.ancestry {
+			p.Ancestry = ancestry(tb, tr, p.Pid)
+		}
+	}
+	ms = append(ms, ts...)
+
 	ps := ProcStats{
 		Count:  len(tb),
 		Active: active,
@@ -138,6 +237,27 @@ func Measure() (ProcStats, []message.Content) {
 	return ps, ms
 }
 
+// Identify returns the identifier of a process from the current process table, enabling other sources'
+// messages to reference the process' messages.
+func Identify(pid Pid) EventID {
+	procLock.RLock()
+	p, ok := procs[pid]
+	procLock.RUnlock()
+	if !ok {
+		return EventID{Pid: pid}
+	}
+	return p.EventID
+}
+
+// previous returns a process' measurement from the previous process table, unless its pid has since been reused.
+func previous(ptb Table, p *Process) (*Process, bool) {
+	pp, ok := ptb[p.Pid]
+	if !ok || !pp.Starttime.Equal(p.Starttime) {
+		return nil, false
+	}
+	return pp, true
+}
+
 // buildTable builds a process table and captures current process state.
 func buildTable() Table {
 	pids, err := getPids()
@@ -149,10 +269,12 @@ func buildTable() Table {
 	epm := epMap
 	epLock.RUnlock()
 
+	snapshot(pids)
 	tb := make(map[Pid]*Measurement, len(pids))
 	for _, pid := range pids {
 		id, props, metrics := pid.metrics()
 		props.Connections = epm[pid]
+		metrics.Remotes = remotes(props.Connections)
 		tb[pid] = &Measurement{
 			Header:     message.Measurement(),
 			EventID:    id,
@@ -11,6 +11,7 @@ import (
 	"unsafe"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
 )
 
 /*
@@ -212,6 +213,16 @@ func (pid Pid) io() Io {
 	}
 }
 
+// delays captures the scheduler and delay accounting metrics of a process. Unsupported on darwin.
+func (pid Pid) delays() Delays {
+	return Delays{}
+}
+
+// tasks captures the measurements of each thread of a process. Unsupported on darwin.
+func (p *Process) tasks() []message.Content {
+	return nil
+}
+
 // commandLine retrieves process command, arguments, and environment.
 func (pid Pid) commandLine() CommandLine {
 	clLock.Lock()
@@ -252,7 +263,7 @@ func (pid Pid) commandLine() CommandLine {
 		Executable: executable,
 		Args:       args,
 		Envs:       envs,
-	}
+	}.redact()
 	clMap[pid] = cl
 	return cl
 }
@@ -404,3 +415,9 @@ func (pid Pid) taskMetrics() (time.Duration, time.Duration) {
 
 // 	return nil
 // }
+
+// snapshot captures host wide state for the process table. Not required on this platform.
+func snapshot(_ []Pid) {}
+
+// openPrivileged opens the resources of the process measurements that require root authority. None on this platform.
+func openPrivileged() {}
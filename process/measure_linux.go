@@ -3,14 +3,23 @@
 package process
 
 import (
+	"bufio"
+	"cmp"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
+	"golang.org/x/sys/unix"
 )
 
 var (
@@ -24,13 +33,39 @@ var (
 		'X': "Dead",
 	}
 
+	// seccomp maps seccomp modes to names.
+	seccomp = map[string]string{
+		"0": "disabled",
+		"1": "strict",
+		"2": "filter",
+	}
+
+	// tq contains the netlink descriptors for querying taskstats.
+	tq      = handle{fd: -1, gd: -1, id: -1}
+	tqRetry time.Time // after a failure to open tq
+	tqLock  sync.Mutex
+
+	// sockBytes maps the inodes of TCP sockets to their acknowledged and received byte counts.
+	sockBytes map[uint32][2]uint64
+
+	// netTotals accumulates each process' network traffic across the lifetimes of its sockets.
+	netTotals = map[Pid]*netTotal{}
+
+	// netns maps processes outside the host's network namespace to their namespace, and
+	// netnsOwner maps each such namespace to the lowest pid in it, to which its traffic is reported.
+	netns      map[Pid]string
+	netnsOwner map[string]Pid
+
+	// hostNetns identifies the host's network namespace, resolved with the first snapshot.
+	hostNetns string
+
 	// factor is the system units for CPU time (i.e. "ticks" or "jiffies").
 	factor = 10000 * time.Microsecond
 )
 
 // id captures the process identifier.
 func (pid Pid) id() EventID {
-	buf, err := os.ReadFile(filepath.Join("/proc", pid.String(), "stat"))
+	buf, err := os.ReadFile(sysroot.Proc(pid.String(), "stat"))
 	if err != nil {
 		gocore.Error("ReadFile", err).Err()
 		return EventID{}
@@ -50,14 +85,18 @@ func (pid Pid) id() EventID {
 
 // metrics captures the metrics for a process.
 func (pid Pid) metrics() (EventID, Properties, Metrics) {
-	buf, err := os.ReadFile(filepath.Join("/proc", pid.String(), "stat"))
+	buf, err := os.ReadFile(sysroot.Proc(pid.String(), "stat"))
 	if err != nil {
 		gocore.Error("ReadFile", err).Err()
 		return EventID{Pid: pid}, Properties{}, Metrics{}
 	}
 	fields := strings.Fields(string(buf))
 
-	m, _ := gocore.Measures(filepath.Join("/proc", pid.String(), "status"))
+	st := pid.status()
+	m := make(map[string]string, len(st)) // first value of each line, as gocore.Measures reads
+	for k, v := range st {
+		m[k] = v[0]
+	}
 
 	ppid, _ := strconv.Atoi(fields[3])
 	pgid, _ := strconv.Atoi(fields[4])
@@ -85,9 +124,19 @@ func (pid Pid) metrics() (EventID, Properties, Metrics) {
 	voluntaryContextSwitches, _ := strconv.Atoi(m["voluntary_ctxt_switches"])
 	nonVoluntaryContextSwitches, _ := strconv.Atoi(m["nonvoluntary_ctxt_switches"])
 
+	name := fields[1][1 : len(fields[1])-1]
+	fds, sockets := pid.fds()
+	var memory Memory
+	var mappings []Mapping
+	if watched(name) {
+		memory = pid.memory()
+		memory.NumaNodes = pid.numaNodes()
+		mappings = pid.mappings()
+	}
+
 	return EventID{
 			ppid:      Pid(ppid),
-			Name:      fields[1][1 : len(fields[1])-1],
+			Name:      name,
 			Pid:       pid,
 			Starttime: gocore.Boottime.Add(time.Duration(start) * factor),
 		},
@@ -96,21 +145,22 @@ func (pid Pid) metrics() (EventID, Properties, Metrics) {
 			Pgid:        pgid,
 			Tgid:        tgid,
 			Tty:         fmt.Sprintf("%#.8X", tty),
-			UID:         uid,
-			GID:         gid,
+			Uid:         uid,
+			Gid:         gid,
 			Username:    gocore.Username(uid),
 			Groupname:   gocore.Groupname(gid),
 			Status:      status[fields[2][0]],
 			Nice:        nice,
 			CommandLine: pid.commandLine(),
 			Directories: pid.directories(),
+			Security:    pid.security(st),
 		},
 		Metrics{
 			Priority:                    priority,
 			Threads:                     threads,
-			User:                        time.Duration(user),
-			System:                      time.Duration(system),
-			Total:                       time.Duration(user + system),
+			User:                        time.Duration(user) * factor,
+			System:                      time.Duration(system) * factor,
+			Total:                       time.Duration(user+system) * factor,
 			Size:                        size * 1024,
 			Resident:                    resident * 1024,
 			Share:                       (rssFile + rssShmem) * 1024,
@@ -123,15 +173,39 @@ func (pid Pid) metrics() (EventID, Properties, Metrics) {
 			NonVoluntaryContextSwitches: nonVoluntaryContextSwitches,
 			ContextSwitches:             voluntaryContextSwitches + nonVoluntaryContextSwitches,
 			Io:                          pid.io(),
+			Network:                     pid.network(gocore.Boottime.Add(time.Duration(start)*factor), sockets),
+			Fds:                         fds,
+			Memory:                      memory,
+			Mappings:                    mappings,
 		}
 }
 
+// status reads a process' status file into a map of the fields of each line, e.g. the real, effective,
+// saved and filesystem ids of the Uid line.
+func (pid Pid) status() map[string][]string {
+	st := map[string][]string{}
+	buf, err := os.ReadFile(sysroot.Proc(pid.String(), "status"))
+	if err != nil {
+		return st
+	}
+	for line := range strings.Lines(string(buf)) {
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			if fields := strings.Fields(v); len(fields) > 0 {
+				st[k] = fields
+			}
+		}
+	}
+	return st
+}
+
 // io captures process I/O counts.
 func (pid Pid) io() Io {
 	i := Io{}
-	m, err := gocore.Measures(filepath.Join("/proc", pid.String(), "io"))
+	m, err := gocore.Measures(sysroot.Proc(pid.String(), "io"))
 	if err != nil {
-		gocore.Error("Measures", err).Err()
+		if !errors.Is(err, fs.ErrPermission) { // expected without root authority, see Probe
+			gocore.Error("Measures", err).Err()
+		}
 		return i
 	}
 
@@ -145,6 +219,313 @@ func (pid Pid) io() Io {
 	return i
 }
 
+// fds reports counts of a process' open file descriptors by type and its open files limit, and lists the
+// inodes of its sockets. The counts come from the most recent lsof pass, or, without lsof, from the
+// process' fd directory.
+func (pid Pid) fds() (Fds, []uint32) {
+	var f Fds
+	var sockets []uint32
+	if lsofSpawned.Load() {
+		epLock.RLock()
+		t, ok := fdMap[pid]
+		epLock.RUnlock()
+		if ok {
+			f, sockets = t.Fds, t.sockets
+		}
+	} else {
+		f, sockets = pid.fdDirectory()
+	}
+	if f.FdCount == 0 {
+		return f, sockets
+	}
+
+	if buf, err := os.ReadFile(sysroot.Proc(pid.String(), "limits")); err == nil {
+		for l := range strings.Lines(string(buf)) {
+			if strings.HasPrefix(l, "Max open files") {
+				f.FdLimit, _ = strconv.Atoi(strings.Fields(l)[3]) // soft limit
+				break
+			}
+		}
+	}
+	if f.FdLimit > 0 {
+		f.FdPercent = 100.0 * float64(f.FdCount) / float64(f.FdLimit)
+	}
+
+	return f, sockets
+}
+
+// fdDirectory counts a process' open file descriptors by type from its fd directory.
+func (pid Pid) fdDirectory() (Fds, []uint32) {
+	f := Fds{}
+	var sockets []uint32
+	dirname := sysroot.Proc(pid.String(), "fd")
+	dir, err := os.Open(dirname)
+	if err != nil {
+		return f, nil // insufficient privilege or process exited
+	}
+	ns, err := dir.Readdirnames(0)
+	dir.Close()
+	if err != nil {
+		return f, nil
+	}
+
+	f.FdCount = len(ns)
+	for _, n := range ns {
+		link, err := os.Readlink(filepath.Join(dirname, n))
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(link, "socket:"):
+			f.FdSockets++
+			if inode, err := strconv.ParseUint(link[8:len(link)-1], 10, 32); err == nil { // socket:[inode]
+				sockets = append(sockets, uint32(inode))
+			}
+		case strings.HasPrefix(link, "pipe:"):
+			f.FdPipes++
+		case strings.HasPrefix(link, "anon_inode:"):
+			f.FdAnonInodes++
+		default:
+			f.FdFiles++
+		}
+	}
+
+	return f, sockets
+}
+
+// netTotal accumulates a process' network traffic. The byte counts of its sockets at the previous
+// measurement identify the traffic since, so that traffic of closed sockets is retained.
+type netTotal struct {
+	starttime time.Time
+	sent      int
+	received  int
+	sockets   map[uint32][2]uint64
+}
+
+// snapshot captures the byte counts of the TCP sockets of the host's network namespace and
+// the network namespaces of the processes for the process table.
+func snapshot(pids []Pid) {
+	if hostNetns == "" {
+		if sysroot.Relocated() {
+			hostNetns, _ = os.Readlink(sysroot.Proc("1", "ns", "net"))
+		} else {
+			hostNetns, _ = os.Readlink("/proc/self/ns/net")
+		}
+	}
+
+	sb, err := nlSocketBytes()
+	if err != nil {
+		gocore.Error("nlSocketBytes", err).Err()
+	}
+	sockBytes = sb
+
+	live := make(map[Pid]struct{}, len(pids))
+	netns = map[Pid]string{}
+	netnsOwner = map[string]Pid{}
+	for _, pid := range pids {
+		live[pid] = struct{}{}
+		ns, err := os.Readlink(sysroot.Proc(pid.String(), "ns", "net"))
+		if err != nil || ns == hostNetns {
+			continue
+		}
+		netns[pid] = ns
+		if owner, ok := netnsOwner[ns]; !ok || pid < owner {
+			netnsOwner[ns] = pid
+		}
+	}
+
+	for pid := range netTotals {
+		if _, ok := live[pid]; !ok {
+			delete(netTotals, pid)
+		}
+	}
+}
+
+// network reports the bytes a process has sent and received over its TCP sockets. Netlink does not
+// report the sockets of other network namespaces, so the traffic of such a namespace's interfaces is
+// reported once, for the lowest pid in the namespace.
+func (pid Pid) network(starttime time.Time, sockets []uint32) Network {
+	var n Network
+	if ns, ok := netns[pid]; ok {
+		if netnsOwner[ns] != pid {
+			return n
+		}
+		buf, err := os.ReadFile(sysroot.Proc(pid.String(), "net", "dev"))
+		if err != nil {
+			return n
+		}
+		for l := range strings.Lines(string(buf)) {
+			name, counts, ok := strings.Cut(l, ":")
+			if !ok || strings.TrimSpace(name) == "lo" {
+				continue
+			}
+			fields := strings.Fields(counts)
+			if len(fields) < 9 {
+				continue
+			}
+			received, _ := strconv.Atoi(fields[0])
+			sent, _ := strconv.Atoi(fields[8])
+			n.NetworkReceived += received
+			n.NetworkSent += sent
+		}
+		return n
+	}
+
+	t, ok := netTotals[pid]
+	if !ok || !t.starttime.Equal(starttime) { // pid reused
+		t = &netTotal{starttime: starttime}
+		netTotals[pid] = t
+	}
+	curr := make(map[uint32][2]uint64, len(sockets))
+	for _, inode := range sockets {
+		b, ok := sockBytes[inode]
+		if !ok {
+			continue
+		}
+		curr[inode] = b
+		prev := t.sockets[inode]
+		if b[0] < prev[0] || b[1] < prev[1] { // inode reused
+			prev = [2]uint64{}
+		}
+		t.sent += int(b[0] - prev[0])
+		t.received += int(b[1] - prev[1])
+	}
+	t.sockets = curr
+
+	n.NetworkSent = t.sent
+	n.NetworkReceived = t.received
+	return n
+}
+
+// memory captures a process' detailed memory metrics from its smaps rollup.
+func (pid Pid) memory() Memory {
+	m, err := gocore.Measures(sysroot.Proc(pid.String(), "smaps_rollup"))
+	if err != nil {
+		if !exited(err) {
+			gocore.Error("Measures", err).Err()
+		}
+		return Memory{}
+	}
+
+	pss, _ := strconv.Atoi(m["Pss"])
+	privateClean, _ := strconv.Atoi(m["Private_Clean"])
+	privateDirty, _ := strconv.Atoi(m["Private_Dirty"])
+	swap, _ := strconv.Atoi(m["Swap"])
+	swapPss, _ := strconv.Atoi(m["SwapPss"])
+	anonymous, _ := strconv.Atoi(m["Anonymous"])
+	pssFile, _ := strconv.Atoi(m["Pss_File"])
+	pssShmem, _ := strconv.Atoi(m["Pss_Shmem"])
+	locked, _ := strconv.Atoi(m["Locked"])
+
+	return Memory{
+		Pss:        pss * 1024,
+		Uss:        (privateClean + privateDirty) * 1024,
+		Swap:       swap * 1024,
+		SwapPss:    swapPss * 1024,
+		Anonymous:  anonymous * 1024,
+		FileBacked: pssFile * 1024,
+		Shmem:      pssShmem * 1024,
+		Locked:     locked * 1024,
+	}
+}
+
+// numaNodes sums the sizes of a process' memory pages resident on each NUMA node.
+func (pid Pid) numaNodes() map[string]int {
+	f, err := os.Open(sysroot.Proc(pid.String(), "numa_maps"))
+	if err != nil {
+		return nil // kernel without NUMA support or process exited
+	}
+	defer f.Close()
+
+	nodes := map[string]int{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// e.g. 55887a629000 default file=/usr/bin/head mapped=2 N0=2 kernelpagesize_kB=4
+		pages := map[string]int{}
+		size := 4096
+		for _, field := range strings.Fields(sc.Text())[2:] {
+			k, v, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				continue
+			}
+			if k == "kernelpagesize_kB" {
+				size = n * 1024
+			} else if len(k) > 1 && k[0] == 'N' && k[1] >= '0' && k[1] <= '9' {
+				pages[k] = n
+			}
+		}
+		for node, n := range pages {
+			nodes[node] += n * size
+		}
+	}
+
+	return nodes
+}
+
+// mappings captures a process' memory mappings with the largest proportional set size.
+func (pid Pid) mappings() []Mapping {
+	if flags.mappings == 0 {
+		return nil
+	}
+
+	f, err := os.Open(sysroot.Proc(pid.String(), "smaps"))
+	if err != nil {
+		if !exited(err) {
+			gocore.Error("Open", err).Err()
+		}
+		return nil
+	}
+	defer f.Close()
+
+	var ms []Mapping
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(fields[0], ":") { // header line: address perms offset dev inode [path]
+			if len(fields) < 5 {
+				continue
+			}
+			m := Mapping{
+				Address: fields[0],
+				Perms:   fields[1],
+			}
+			if len(fields) > 5 {
+				m.Path = strings.Join(fields[5:], " ")
+			}
+			ms = append(ms, m)
+			continue
+		}
+		if len(ms) == 0 || len(fields) < 2 {
+			continue
+		}
+		n, _ := strconv.Atoi(fields[1])
+		switch fields[0] {
+		case "Rss:":
+			ms[len(ms)-1].Resident = n * 1024
+		case "Pss:":
+			ms[len(ms)-1].Pss = n * 1024
+		case "Swap:":
+			ms[len(ms)-1].Swap = n * 1024
+		}
+	}
+
+	slices.SortFunc(ms, func(a, b Mapping) int {
+		return cmp.Compare(b.Pss, a.Pss)
+	})
+	if len(ms) > int(flags.mappings) {
+		ms = ms[:flags.mappings]
+	}
+
+	return ms
+}
+
 // commandLine retrieves process command, arguments, and environment.
 func (pid Pid) commandLine() CommandLine {
 	clLock.Lock()
@@ -154,15 +535,16 @@ func (pid Pid) commandLine() CommandLine {
 	}
 
 	cl := CommandLine{}
-	cl.Executable, _ = os.Readlink(filepath.Join("/proc", pid.String(), "exe"))
-	if arg, err := os.ReadFile(filepath.Join("/proc", pid.String(), "cmdline")); err == nil && len(arg) > 2 {
-		cl.Args = strings.Split(string(arg[:len(arg)-2]), "\x00")
+	cl.Executable, _ = os.Readlink(sysroot.Proc(pid.String(), "exe"))
+	if arg, err := os.ReadFile(sysroot.Proc(pid.String(), "cmdline")); err == nil && len(arg) > 1 {
+		cl.Args = strings.Split(strings.TrimSuffix(string(arg), "\x00"), "\x00")
 		cl.Args = cl.Args[1:]
 	}
-	if env, err := os.ReadFile(filepath.Join("/proc", pid.String(), "environ")); err == nil {
-		cl.Envs = strings.Split(string(env), "\x00")
+	if env, err := os.ReadFile(sysroot.Proc(pid.String(), "environ")); err == nil && len(env) > 0 {
+		cl.Envs = strings.Split(strings.TrimSuffix(string(env), "\x00"), "\x00")
 	}
 
+	cl = cl.redact()
 	clMap[pid] = cl
 	return cl
 }
@@ -170,14 +552,154 @@ func (pid Pid) commandLine() CommandLine {
 // directories captures process directories.
 func (pid Pid) directories() Directories {
 	d := Directories{}
-	d.Cwd, _ = os.Readlink(filepath.Join("/proc", pid.String(), "cwd"))
-	d.Root, _ = os.Readlink(filepath.Join("/proc", pid.String(), "root"))
+	d.Cwd, _ = os.Readlink(sysroot.Proc(pid.String(), "cwd"))
+	d.Root, _ = os.Readlink(sysroot.Proc(pid.String(), "root"))
 	return d
 }
 
+// delays captures the scheduler and delay accounting metrics of a process. The run queue delay is
+// summed from its threads' schedstat. Block I/O, swapin, and thrashing delays require that the kernel's
+// delay accounting is enabled (sysctl kernel.task_delayacct=1).
+func (pid Pid) delays() Delays {
+	var d Delays
+	dirname := sysroot.Proc(pid.String(), "task")
+	if dir, err := os.Open(dirname); err == nil {
+		tids, _ := dir.Readdirnames(0)
+		dir.Close()
+		for _, tid := range tids {
+			buf, err := os.ReadFile(filepath.Join(dirname, tid, "schedstat"))
+			if err != nil {
+				continue
+			}
+			fields := strings.Fields(string(buf)) // time on cpu, time on run queue, timeslices
+			if len(fields) < 3 {
+				continue
+			}
+			delay, _ := strconv.ParseInt(fields[1], 10, 64)
+			timeslices, _ := strconv.Atoi(fields[2])
+			d.RunDelay += time.Duration(delay)
+			d.Timeslices += timeslices
+		}
+	}
+
+	tqLock.Lock()
+	ts, err := queryTaskstats(pid)
+	tqLock.Unlock()
+	if err != nil {
+		return d // process exited, or taskstats unavailable
+	}
+	d.RunDelay = max(d.RunDelay, time.Duration(ts.Cpu_delay_total)) // includes exited threads
+	d.BlkioDelay = time.Duration(ts.Blkio_delay_total)
+	d.SwapinDelay = time.Duration(ts.Swapin_delay_total)
+	d.ThrashingDelay = time.Duration(ts.Thrashing_delay_total)
+
+	return d
+}
+
+// queryTaskstats queries the taskstats of a process, first opening the query socket if necessary.
+// After a failure to open the socket, a later sample retries no sooner than a minute later.
+// The caller must hold tqLock.
+func queryTaskstats(pid Pid) (*unix.Taskstats, error) {
+	if tq.gd < 0 {
+		if time.Now().Before(tqRetry) {
+			return nil, syscall.EAGAIN
+		}
+		if err := openTaskstats(); err != nil {
+			tqRetry = time.Now().Add(time.Minute)
+			gocore.Error("taskstats", err).Err()
+			return nil, err
+		}
+	}
+	return nlTaskstats(tq.gd, tq.id, pid)
+}
+
+// openPrivileged opens the taskstats query socket while gomon has root authority.
+func openPrivileged() {
+	tqLock.Lock()
+	defer tqLock.Unlock()
+	if tq.gd < 0 {
+		if err := openTaskstats(); err != nil {
+			tqRetry = time.Now().Add(time.Minute)
+			gocore.Error("taskstats", err).Warn()
+		}
+	}
+}
+
+// openTaskstats opens the netlink socket for querying taskstats. The caller must hold tqLock.
+func openTaskstats() error {
+	gd, err := nlGeneric()
+	if err != nil {
+		return err
+	}
+	id, err := genlFamily(gd, unix.TASKSTATS_GENL_NAME)
+	if err != nil {
+		syscall.Close(gd)
+		return err
+	}
+	// a lost reply must not stall the measurement
+	if err := syscall.SetsockoptTimeval(gd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &syscall.Timeval{Sec: 1}); err != nil {
+		syscall.Close(gd)
+		return gocore.Error("SetsockoptTimeval", err)
+	}
+	tq = handle{fd: -1, gd: gd, id: id}
+	return nil
+}
+
+// security captures the security context of a process from its status, namespaces, and LSM attributes.
+func (pid Pid) security(st map[string][]string) Security {
+	first := func(k string) string {
+		if v := st[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	s := Security{
+		CapEff:  first("CapEff"),
+		CapPrm:  first("CapPrm"),
+		Seccomp: seccomp[first("Seccomp")],
+	}
+	s.NoNewPrivs, _ = strconv.Atoi(first("NoNewPrivs"))
+
+	// the status file's Uid and Gid lines list the real, effective, saved, and filesystem ids
+	if ids := st["Uid"]; len(ids) > 2 {
+		s.Euid, _ = strconv.Atoi(ids[1])
+		s.Suid, _ = strconv.Atoi(ids[2])
+	}
+	if ids := st["Gid"]; len(ids) > 2 {
+		s.Egid, _ = strconv.Atoi(ids[1])
+		s.Sgid, _ = strconv.Atoi(ids[2])
+	}
+
+	if buf, err := os.ReadFile(sysroot.Proc(pid.String(), "attr", "current")); err == nil {
+		s.Label = strings.TrimRight(string(buf), "\x00\n")
+	}
+
+	dirname := sysroot.Proc(pid.String(), "ns")
+	if dir, err := os.Open(dirname); err == nil {
+		ns, _ := dir.Readdirnames(0)
+		dir.Close()
+		for _, n := range ns {
+			link, err := os.Readlink(filepath.Join(dirname, n)) // e.g. net:[4026531840]
+			if err != nil {
+				continue
+			}
+			if _, inode, ok := strings.Cut(link, "["); ok {
+				if ino, err := strconv.ParseInt(strings.TrimSuffix(inode, "]"), 10, 64); err == nil {
+					if s.Namespaces == nil {
+						s.Namespaces = map[string]int64{}
+					}
+					s.Namespaces[n] = ino
+				}
+			}
+		}
+	}
+
+	return s
+}
+
 // getPids gets the list of active processes by pid.
 func getPids() ([]Pid, error) {
-	dir, err := os.Open("/proc")
+	dir, err := os.Open(sysroot.Proc())
 	if err != nil {
 		return nil, gocore.Error("/proc", err)
 	}
@@ -198,3 +720,9 @@ func getPids() ([]Pid, error) {
 
 	return pids[:i], nil
 }
+
+// exited reports whether an error reading a process' procfs files indicates that the process exited
+// after it was listed, an expected race.
+func exited(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ESRCH)
+}
@@ -0,0 +1,139 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+// pid of the process recorded in testdata/proc.
+const pid Pid = 4242
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("procfs", "testdata/proc")
+	gocore.Flags.FlagSet.Set("watch", "^sleep$")
+	gocore.Flags.FlagSet.Set("mappings", "2")
+	os.Exit(m.Run())
+}
+
+func TestId(t *testing.T) {
+	want := EventID{
+		ppid:      4240,
+		Name:      "sleep",
+		Pid:       pid,
+		Starttime: gocore.Boottime.Add(672614 * factor),
+	}
+	if got := pid.id(); got != want {
+		t.Errorf("id() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	id, p, m := pid.metrics()
+	if id.Name != "sleep" || id.ppid != 4240 || !id.Starttime.Equal(gocore.Boottime.Add(672614*factor)) {
+		t.Errorf("metrics() id = %+v", id)
+	}
+
+	if p.Ppid != 4240 || p.Pgid != 4242 || p.Tty != "0X00008800" || p.Uid != 1000 || p.Gid != 100 ||
+		p.Status != "Sleeping" || p.Nice != 0 {
+		t.Errorf("metrics() properties = %+v", p)
+	}
+	if p.Executable != "/usr/bin/sleep" || !slices.Equal(p.Args, []string{"300"}) ||
+		!slices.Equal(p.Envs, []string{"HOME=/home/user", "DB_PASSWORD=" + redacted}) {
+		t.Errorf("metrics() command line = %+v", p.CommandLine)
+	}
+	if p.Cwd != "/home/user" || p.Root != "/" {
+		t.Errorf("metrics() directories = %+v", p.Directories)
+	}
+
+	s := p.Security
+	if s.Euid != 1001 || s.Suid != 1002 || s.Egid != 101 || s.Sgid != 102 ||
+		s.CapEff != "0000000000001000" || s.CapPrm != "0000000000003000" ||
+		s.Seccomp != "filter" || s.NoNewPrivs != 1 || s.Label != "unconfined" ||
+		!maps.Equal(s.Namespaces, map[string]int64{"net": 4026531840, "pid": 4026531836}) {
+		t.Errorf("metrics() security = %+v", s)
+	}
+
+	if m.Priority != 20 || m.Threads != 1 ||
+		m.User != 25*factor || m.System != 12*factor || m.Total != 37*factor {
+		t.Errorf("metrics() cpu = %d %d %v %v %v", m.Priority, m.Threads, m.User, m.System, m.Total)
+	}
+	if m.Size != 2496*1024 || m.Resident != 1436*1024 || m.Share != (1340+4)*1024 ||
+		m.VirtualMemoryMax != 2500*1024 || m.ResidentMemoryMax != 1440*1024 {
+		t.Errorf("metrics() memory sizes = %d %d %d %d %d",
+			m.Size, m.Resident, m.Share, m.VirtualMemoryMax, m.ResidentMemoryMax)
+	}
+	if m.MinorFaults != 117 || m.MajorFaults != 3 || m.PageFaults != 120 ||
+		m.VoluntaryContextSwitches != 7 || m.NonVoluntaryContextSwitches != 2 || m.ContextSwitches != 9 {
+		t.Errorf("metrics() faults and context switches = %+v", m)
+	}
+	if want := (Io{ReadRequested: 3980, WriteRequested: 120, ReadActual: 4096, WriteActual: 8192, ReadOperations: 9, WriteOperations: 2}); m.Io != want {
+		t.Errorf("metrics() io = %+v, want %+v", m.Io, want)
+	}
+	if want := (Fds{FdCount: 4, FdLimit: 1024, FdPercent: 100.0 * 4 / 1024, FdFiles: 1, FdSockets: 1, FdPipes: 1, FdAnonInodes: 1}); m.Fds != want {
+		t.Errorf("metrics() fds = %+v, want %+v", m.Fds, want)
+	}
+	if len(m.Mappings) != 2 {
+		t.Errorf("metrics() mappings = %+v", m.Mappings)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	want := Memory{
+		Pss:        352 * 1024,
+		Uss:        (36 + 96) * 1024,
+		Swap:       16 * 1024,
+		SwapPss:    8 * 1024,
+		Anonymous:  96 * 1024,
+		FileBacked: 256 * 1024,
+		Shmem:      4 * 1024,
+		Locked:     4 * 1024,
+	}
+	if got := pid.memory(); !memoryEqual(got, want) {
+		t.Errorf("memory() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNumaNodes(t *testing.T) {
+	want := map[string]int{
+		"N0": (2 + 200 + 3) * 4096,
+		"N1": (1+8)*4096 + 1*2048*1024, // including a huge page
+	}
+	if got := pid.numaNodes(); !maps.Equal(got, want) {
+		t.Errorf("numaNodes() = %v, want %v", got, want)
+	}
+}
+
+func TestMappings(t *testing.T) {
+	want := []Mapping{
+		{Path: "/usr/lib/x86_64-linux-gnu/libc.so.6", Address: "7f5197f90000-7f51980e6000", Perms: "r-xp", Resident: 832 * 1024, Pss: 104 * 1024},
+		{Path: "[heap]", Address: "556e9b8c2000-556e9b8e3000", Perms: "rw-p", Resident: 12 * 1024, Pss: 12 * 1024, Swap: 4 * 1024},
+	}
+	if got := pid.mappings(); !slices.Equal(got, want) {
+		t.Errorf("mappings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExited(t *testing.T) {
+	var exited Pid = 4243 // not in testdata/proc, as if the process exited
+	if m := exited.mappings(); m != nil {
+		t.Errorf("mappings() of exited process = %+v", m)
+	}
+	if m := exited.memory(); !memoryEqual(m, Memory{}) {
+		t.Errorf("memory() of exited process = %+v", m)
+	}
+	if n := exited.numaNodes(); n != nil {
+		t.Errorf("numaNodes() of exited process = %v", n)
+	}
+}
+
+// memoryEqual compares memory metrics, which contain a map and so are not comparable.
+func memoryEqual(a, b Memory) bool {
+	return reflect.DeepEqual(a, b)
+}
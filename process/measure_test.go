@@ -0,0 +1,30 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrevious(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	ptb := table()
+	ptb[200].Starttime = start
+	ptb[201].Starttime = start
+
+	tb := table()
+	tb[200].Starttime = start
+	tb[201].Starttime = start.Add(time.Minute) // pid reused by another process
+	tb[300] = &Process{EventID: EventID{Name: "new", Pid: 300, Starttime: start}}
+
+	if pp, ok := previous(ptb, tb[200]); !ok || pp != ptb[200] {
+		t.Errorf("previous() of continuing process = %v, %t", pp, ok)
+	}
+	if pp, ok := previous(ptb, tb[201]); ok {
+		t.Errorf("previous() of reused pid = %v", pp)
+	}
+	if pp, ok := previous(ptb, tb[300]); ok {
+		t.Errorf("previous() of new process = %v", pp)
+	}
+}
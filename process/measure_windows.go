@@ -7,6 +7,7 @@ import (
 	"unsafe"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
 
 	"github.com/yusufpapurcu/wmi"
 
@@ -217,6 +218,16 @@ func (pid Pid) io() Io {
 	}
 }
 
+// delays captures the scheduler and delay accounting metrics of a process. Unsupported on windows.
+func (pid Pid) delays() Delays {
+	return Delays{}
+}
+
+// tasks captures the measurements of each thread of a process. Unsupported on windows.
+func (p *Process) tasks() []message.Content {
+	return nil
+}
+
 // commandLine retrieves process command, arguments, and environment.
 func (pid Pid) commandLine() CommandLine {
 	// this could be populated with the results of the Win32_Process CommandLine field
@@ -249,3 +260,6 @@ func getPids() ([]Pid, error) {
 	}
 	return pids, nil
 }
+
+// snapshot captures host wide state for the process table. Not required on this platform.
+func snapshot(_ []Pid) {}
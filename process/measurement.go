@@ -43,9 +43,10 @@ type (
 
 	// Connection represents an inter-process or host/data connection.
 	Connection struct {
-		Type string   `json:"type" gomon:"property"`
-		Self Endpoint `json:"self" gomon:"property"`
-		Peer Endpoint `json:"peer" gomon:"property"`
+		Type  string   `json:"type" gomon:"property"`
+		Self  Endpoint `json:"self" gomon:"property"`
+		Peer  Endpoint `json:"peer" gomon:"property"`
+		Class string   `json:"class,omitempty" gomon:"property"` // of remote host peer
 	}
 
 	// Properties defines measurement properties.
@@ -60,11 +61,27 @@ type (
 		Groupname   string `json:"groupname,omitempty" gomon:"property,,!windows"`
 		Status      string `json:"status" gomon:"enum,none"`
 		Nice        int    `json:"nice,omitempty" gomon:"gauge,none,!windows"`
+		Ancestry    string `json:"ancestry,omitempty" gomon:"property"`
 		CommandLine `gomon:""`
 		Directories `gomon:""`
+		Security    `gomon:""`
 		Connections []Connection `json:"connections" gomon:"property"`
 	}
 
+	// Security contains a process' security context.
+	Security struct {
+		Euid       int              `json:"euid,omitempty" gomon:"property,,linux"`
+		Suid       int              `json:"suid,omitempty" gomon:"property,,linux"`
+		Egid       int              `json:"egid,omitempty" gomon:"property,,linux"`
+		Sgid       int              `json:"sgid,omitempty" gomon:"property,,linux"`
+		CapEff     string           `json:"cap_eff,omitempty" gomon:"property,,linux"`
+		CapPrm     string           `json:"cap_prm,omitempty" gomon:"property,,linux"`
+		Seccomp    string           `json:"seccomp,omitempty" gomon:"property,,linux"`
+		NoNewPrivs int              `json:"no_new_privs,omitempty" gomon:"property,,linux"`
+		Label      string           `json:"label,omitempty" gomon:"property,,linux"`
+		Namespaces map[string]int64 `json:"namespaces,omitempty" gomon:"property,,linux"`
+	}
+
 	// Io contains a process' I/O metrics.
 	Io struct {
 		ReadActual      int `json:"read_actual" gomon:"counter,B"`
@@ -75,25 +92,80 @@ type (
 		WriteOperations int `json:"write_operations,omitempty" gomon:"counter,count,!darwin"`
 	}
 
+	// Network contains a process' network traffic metrics.
+	Network struct {
+		NetworkSent     int `json:"network_sent,omitempty" gomon:"counter,B,linux"`     // acknowledged bytes of the TCP sockets observed
+		NetworkReceived int `json:"network_received,omitempty" gomon:"counter,B,linux"` // received bytes of the TCP sockets observed
+	}
+
+	// Fds contains a process' open file descriptor metrics.
+	Fds struct {
+		FdCount      int     `json:"fd_count,omitempty" gomon:"gauge,count,linux"`
+		FdLimit      int     `json:"fd_limit,omitempty" gomon:"gauge,count,linux"`
+		FdPercent    float64 `json:"fd_percent,omitempty" gomon:"gauge,%,linux"`
+		FdFiles      int     `json:"fd_files,omitempty" gomon:"gauge,count,linux"`
+		FdSockets    int     `json:"fd_sockets,omitempty" gomon:"gauge,count,linux"`
+		FdPipes      int     `json:"fd_pipes,omitempty" gomon:"gauge,count,linux"`
+		FdAnonInodes int     `json:"fd_anon_inodes,omitempty" gomon:"gauge,count,linux"`
+	}
+
+	// Delays contains a process' scheduler run queue and delay accounting metrics.
+	Delays struct {
+		RunDelay       time.Duration `json:"run_delay,omitempty" gomon:"counter,ns,linux"`
+		Timeslices     int           `json:"timeslices,omitempty" gomon:"counter,count,linux"`
+		BlkioDelay     time.Duration `json:"blkio_delay,omitempty" gomon:"counter,ns,linux"`
+		SwapinDelay    time.Duration `json:"swapin_delay,omitempty" gomon:"counter,ns,linux"`
+		ThrashingDelay time.Duration `json:"thrashing_delay,omitempty" gomon:"counter,ns,linux"`
+	}
+
+	// Memory contains a watched process' detailed memory metrics.
+	Memory struct {
+		Pss        int            `json:"pss,omitempty" gomon:"gauge,B,linux"`
+		Uss        int            `json:"uss,omitempty" gomon:"gauge,B,linux"`
+		Swap       int            `json:"swap,omitempty" gomon:"gauge,B,linux"`
+		SwapPss    int            `json:"swap_pss,omitempty" gomon:"gauge,B,linux"`
+		Anonymous  int            `json:"anonymous,omitempty" gomon:"gauge,B,linux"`
+		FileBacked int            `json:"file_backed,omitempty" gomon:"gauge,B,linux"`
+		Shmem      int            `json:"shmem,omitempty" gomon:"gauge,B,linux"`
+		Locked     int            `json:"locked,omitempty" gomon:"gauge,B,linux"`
+		NumaNodes  map[string]int `json:"numa_nodes,omitempty" gomon:"gauge,B,linux"` // placement by node, e.g. N0
+	}
+
+	// Mapping contains the memory metrics of one of a watched process' memory mappings.
+	Mapping struct {
+		Path     string `json:"path" gomon:"property"`
+		Address  string `json:"address" gomon:"property"`
+		Perms    string `json:"perms" gomon:"property"`
+		Resident int    `json:"resident" gomon:"gauge,B"`
+		Pss      int    `json:"pss" gomon:"gauge,B"`
+		Swap     int    `json:"swap" gomon:"gauge,B"`
+	}
+
 	// Metrics defines measurement metrics.
 	Metrics struct {
-		Priority                    int           `json:"priority,omitempty" gomon:"gauge,none,!windows"`
-		Threads                     int           `json:"threads" gomon:"gauge,count"`
-		User                        time.Duration `json:"user" gomon:"counter,ns"`
-		System                      time.Duration `json:"system" gomon:"counter,ns"`
-		Total                       time.Duration `json:"total" gomon:"counter,ns"`
-		Size                        int           `json:"size" gomon:"gauge,B"`
-		Resident                    int           `json:"resident" gomon:"gauge,B"`
-		Share                       int           `json:"share,omitempty" gomon:"gauge,B,linux"`
-		VirtualMemoryMax            int           `json:"virtual_memory_max,omitempty" gomon:"counter,B,linux"`
-		ResidentMemoryMax           int           `json:"resident_memory_max,omitempty" gomon:"counter,B,linux"`
-		PageFaults                  int           `json:"page_faults" gomon:"counter,count"`
-		MinorFaults                 int           `json:"minor_faults,omitempty" gomon:"counter,count,linux"`
-		MajorFaults                 int           `json:"major_faults,omitempty" gomon:"counter,count,linux"`
-		VoluntaryContextSwitches    int           `json:"voluntary_context_switches,omitempty" gomon:"counter,count,linux"`
-		NonVoluntaryContextSwitches int           `json:"nonvoluntary_context_switches,omitempty" gomon:"counter,count,linux"`
-		ContextSwitches             int           `json:"context_switches,omitempty" gomon:"counter,count,!windows"`
+		Priority                    int            `json:"priority,omitempty" gomon:"gauge,none,!windows"`
+		Threads                     int            `json:"threads" gomon:"gauge,count"`
+		User                        time.Duration  `json:"user" gomon:"counter,ns"`
+		System                      time.Duration  `json:"system" gomon:"counter,ns"`
+		Total                       time.Duration  `json:"total" gomon:"counter,ns"`
+		Size                        int            `json:"size" gomon:"gauge,B"`
+		Resident                    int            `json:"resident" gomon:"gauge,B"`
+		Share                       int            `json:"share,omitempty" gomon:"gauge,B,linux"`
+		VirtualMemoryMax            int            `json:"virtual_memory_max,omitempty" gomon:"counter,B,linux"`
+		ResidentMemoryMax           int            `json:"resident_memory_max,omitempty" gomon:"counter,B,linux"`
+		PageFaults                  int            `json:"page_faults" gomon:"counter,count"`
+		MinorFaults                 int            `json:"minor_faults,omitempty" gomon:"counter,count,linux"`
+		MajorFaults                 int            `json:"major_faults,omitempty" gomon:"counter,count,linux"`
+		VoluntaryContextSwitches    int            `json:"voluntary_context_switches,omitempty" gomon:"counter,count,linux"`
+		NonVoluntaryContextSwitches int            `json:"nonvoluntary_context_switches,omitempty" gomon:"counter,count,linux"`
+		ContextSwitches             int            `json:"context_switches,omitempty" gomon:"counter,count,!windows"`
+		Remotes                     map[string]int `json:"remotes,omitempty" gomon:"gauge,count"` // remote peers by class
 		Io                          `gomon:""`
+		Network                     `gomon:""`
+		Fds                         `gomon:""`
+		Delays                      `gomon:""`
+		Memory                      `gomon:""`
+		Mappings                    []Mapping `json:"mappings,omitempty" gomon:",,linux"`
 	}
 
 	// Measurement defines the properties and metrics of a process measurement.
@@ -9,10 +9,12 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 	"unsafe"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
 	"golang.org/x/net/bpf"
 	"golang.org/x/sys/unix"
 )
@@ -50,6 +52,15 @@ type (
 		syscall.NlAttr
 	}
 
+	// tsAggregate defines the layout of a taskstats aggregate attribute.
+	tsAggregate struct {
+		attr1 syscall.NlAttr // TASKSTATS_TYPE_AGGR_PID or TASKSTATS_TYPE_AGGR_TGID
+		attr2 syscall.NlAttr // TASKSTATS_TYPE_PID or TASKSTATS_TYPE_TGID
+		pid   uint32
+		attr3 syscall.NlAttr // TASKSTATS_TYPE_STATS
+		ts    unix.Taskstats
+	}
+
 	// nlProcRequest defines netlink process request.
 	nlProcRequest struct {
 		syscall.NlMsghdr
@@ -261,7 +272,7 @@ func nlAttr(nlMsg []byte, key int) ([]byte, error) {
 
 // nlGenericObserve initiate netlink generic connector for family's messages.
 func nlGenericObserve(fd, id int, on bool) error {
-	data := []byte("0-" + strconv.Itoa(runtime.NumCPU()-1) + "\x00")
+	data := []byte(cpumask() + "\x00")
 	t := unix.TASKSTATS_CMD_ATTR_DEREGISTER_CPUMASK
 	if on {
 		t = unix.TASKSTATS_CMD_ATTR_REGISTER_CPUMASK
@@ -288,14 +299,24 @@ func nlGenericObserve(fd, id int, on bool) error {
 	return syscall.Sendto(fd, buf, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
 }
 
-// nlTaskstats queries a specific process for its taskstats.
-func nlTaskstats(pid Pid) error {
+// cpumask returns the list of possible cpus for which to register for exit-time taskstats.
+func cpumask() string {
+	if buf, err := os.ReadFile(sysroot.Sys("devices", "system", "cpu", "possible")); err == nil {
+		if mask := strings.TrimSpace(string(buf)); mask != "" {
+			return mask
+		}
+	}
+	return "0-" + strconv.Itoa(runtime.NumCPU()-1)
+}
+
+// nlTaskstats queries a process' thread group for its taskstats.
+func nlTaskstats(fd, id int, pid Pid) (*unix.Taskstats, error) {
 	data := make([]byte, 4)
 	gocore.HostEndian.PutUint32(data, uint32(pid))
 	req := nlGenlRequest{
 		NlMsghdr: syscall.NlMsghdr{
 			Len:   uint32(syscall.NLMSG_HDRLEN + unix.GENL_HDRLEN + syscall.NLA_HDRLEN + len(data)),
-			Type:  uint16(h.id),
+			Type:  uint16(id),
 			Flags: syscall.NLM_F_REQUEST,
 			Seq:   0,
 			Pid:   uint32(os.Getpid()),
@@ -311,7 +332,44 @@ func nlTaskstats(pid Pid) error {
 	}
 
 	buf := append((*[unsafe.Sizeof(req)]byte)(unsafe.Pointer(&req))[:], data...)
-	return syscall.Sendto(h.gd, buf, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK})
+	if err := syscall.Sendto(fd, buf, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, gocore.Error("Sendto", err)
+	}
+
+	// skip any late replies to earlier requests whose receive timed out
+	nlMsg := make([]byte, connectorMaxMessageSize)
+	for {
+		n, _, err := syscall.Recvfrom(fd, nlMsg, 0)
+		if err != nil {
+			return nil, gocore.Error("Recvfrom", err)
+		}
+		msgs, err := syscall.ParseNetlinkMessage(nlMsg[:n])
+		if err != nil {
+			return nil, gocore.Error("ParseNetlinkMessage", err)
+		}
+
+		for _, m := range msgs {
+			if m.Header.Type == syscall.NLMSG_ERROR {
+				return nil, syscall.Errno(-int32(gocore.HostEndian.Uint32(m.Data[:4])))
+			}
+			data := m.Data[unix.GENL_HDRLEN:]
+			offset := int(unsafe.Offsetof(tsAggregate{}.ts))
+			if len(data) < offset {
+				continue
+			}
+			tsMsg := (*tsAggregate)(unsafe.Pointer(&data[0]))
+			if tsMsg.attr1.Type != unix.TASKSTATS_TYPE_AGGR_TGID || tsMsg.attr3.Type != unix.TASKSTATS_TYPE_STATS {
+				return nil, fmt.Errorf("taskstats for %d not reported", pid)
+			}
+			if Pid(tsMsg.pid) != pid {
+				continue
+			}
+			// the kernel's taskstats version may be shorter than the struct's
+			var ts unix.Taskstats
+			copy((*[unsafe.Sizeof(ts)]byte)(unsafe.Pointer(&ts))[:], data[offset:])
+			return &ts, nil
+		}
+	}
 }
 
 // ***********************************
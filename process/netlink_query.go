@@ -13,6 +13,7 @@ import (
 	"unsafe"
 
 	"github.com/zosmac/gocore"
+	"golang.org/x/sys/unix"
 )
 
 /*
@@ -111,6 +112,9 @@ type (
 const (
 	// SOCK_DIAG_BY_FAMILY is the netlink socket query key
 	sockDiagByFamily = 20
+
+	// TCP_LISTEN state from /usr/include/netinet/tcp.h, also applies to unix sockets
+	tcpListen = 10
 )
 
 // *************************************
@@ -200,7 +204,7 @@ func nlInetPeerInode(addr net.Addr) (int, error) {
 		}
 	}
 
-	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, syscall.NETLINK_SOCK_DIAG)
+	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, unix.NETLINK_SOCK_DIAG)
 	if err != nil {
 		return 0, gocore.Error("netlink socket", err)
 	}
@@ -262,7 +266,7 @@ func nlInetPeerInode(addr net.Addr) (int, error) {
 // unixPeerInode queries netlink to find a unix socket's remote connection inode connected to a local inode.
 // See http://man7.org/linux/man-pages/man7/sock_diag.7.html for API details.
 func nlUnixPeerInode(inode int) (int, error) {
-	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, syscall.NETLINK_SOCK_DIAG)
+	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, unix.NETLINK_SOCK_DIAG)
 	if err != nil {
 		return 0, gocore.Error("netlink socket", err)
 	}
@@ -321,3 +325,149 @@ func nlUnixPeerInode(inode int) (int, error) {
 		}
 	}
 }
+
+// nlListenQueues queries netlink for the accept queue depth and backlog of listening sockets, keyed by inode.
+func nlListenQueues() (map[string][2]int, error) {
+	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil, gocore.Error("netlink socket", err)
+	}
+	defer syscall.Close(s)
+
+	queues := map[string][2]int{}
+	for _, family := range []byte{syscall.AF_INET, syscall.AF_INET6} {
+		req := nlInetRequest{
+			syscall.NlMsghdr{
+				Len:   uint32(unsafe.Sizeof(nlInetRequest{})),
+				Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP,
+				Type:  sockDiagByFamily,
+				Seq:   1,
+			},
+			inetDiagReqV2{
+				sdiagFamily:   family,
+				sdiagProtocol: syscall.IPPROTO_TCP,
+				idiagExt:      inetDiagReqNone,
+				idiagStates:   1 << tcpListen,
+			},
+		}
+		buf := (*[unsafe.Sizeof(req)]byte)(unsafe.Pointer(&req))[:]
+		if err := nlDump(s, buf, func(data []byte) {
+			msg := (*inetDiagMsg)(unsafe.Pointer(&data[0]))
+			// for listening sockets, rqueue is the accept queue depth and wqueue the backlog
+			queues[strconv.FormatUint(uint64(msg.idiagInode), 10)] = [2]int{int(msg.idiagRqueue), int(msg.idiagWqueue)}
+		}); err != nil {
+			return queues, err
+		}
+	}
+
+	req := nlUnixRequest{
+		syscall.NlMsghdr{
+			Len:   uint32(unsafe.Sizeof(nlUnixRequest{})),
+			Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP,
+			Type:  sockDiagByFamily,
+			Seq:   1,
+		},
+		unixDiagReq{
+			sdiagFamily: syscall.AF_UNIX,
+			udiagStates: 1 << tcpListen,
+			udiagShow:   udiagShowRqlen,
+		},
+	}
+	buf := (*[unsafe.Sizeof(req)]byte)(unsafe.Pointer(&req))[:]
+	err = nlDump(s, buf, func(data []byte) {
+		msg := (*unixDiagMsg)(unsafe.Pointer(&data[0]))
+		var attr *syscall.RtAttr
+		for i := rtaAlign(int(unsafe.Sizeof(unixDiagMsg{}))); i+syscall.SizeofRtAttr <= len(data); i += rtaAlign(int(attr.Len)) {
+			attr = (*syscall.RtAttr)(unsafe.Pointer(&data[i]))
+			if attr.Len < syscall.SizeofRtAttr {
+				break
+			}
+			if attr.Type == unixDiagRqlen && int(attr.Len) >= syscall.SizeofRtAttr+8 {
+				queues[strconv.FormatUint(uint64(msg.udiagIno), 10)] = [2]int{
+					int(gocore.HostEndian.Uint32(data[i+4 : i+8])),
+					int(gocore.HostEndian.Uint32(data[i+8 : i+12])),
+				}
+			}
+		}
+	})
+
+	return queues, err
+}
+
+// nlDump sends a netlink dump request and passes the data of each response message to a handler.
+func nlDump(s int, req []byte, handle func([]byte)) error {
+	if err := syscall.Sendto(s, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return gocore.Error("sendto", err)
+	}
+
+	nlMsg := make([]byte, 16384)
+	for {
+		n, _, err := syscall.Recvfrom(s, nlMsg, 0)
+		if n <= 0 || err != nil {
+			return gocore.Error("recvfrom", err)
+		}
+		msgs, _ := syscall.ParseNetlinkMessage(nlMsg[:n])
+
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_ERROR:
+				err := syscall.Errno(-int32(gocore.HostEndian.Uint32(m.Data[:4])))
+				if errors.Is(err, syscall.EINVAL) {
+					return nil // ignore invalid argument, probably an old version of Linux
+				}
+				return gocore.Error("netlink", err)
+			case syscall.NLMSG_DONE:
+				return nil
+			case sockDiagByFamily:
+				handle(m.Data)
+			}
+		}
+	}
+}
+
+// nlSocketBytes queries netlink for the acknowledged and received byte counts of TCP sockets, keyed by inode.
+func nlSocketBytes() (map[uint32][2]uint64, error) {
+	s, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM, unix.NETLINK_SOCK_DIAG)
+	if err != nil {
+		return nil, gocore.Error("netlink socket", err)
+	}
+	defer syscall.Close(s)
+
+	sb := map[uint32][2]uint64{}
+	for _, family := range []byte{syscall.AF_INET, syscall.AF_INET6} {
+		req := nlInetRequest{
+			syscall.NlMsghdr{
+				Len:   uint32(unsafe.Sizeof(nlInetRequest{})),
+				Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP,
+				Type:  sockDiagByFamily,
+				Seq:   1,
+			},
+			inetDiagReqV2{
+				sdiagFamily:   family,
+				sdiagProtocol: syscall.IPPROTO_TCP,
+				idiagExt:      1 << (inetDiagIinfo - 1),
+				idiagStates:   math.MaxUint32,
+			},
+		}
+		buf := (*[unsafe.Sizeof(req)]byte)(unsafe.Pointer(&req))[:]
+		if err := nlDump(s, buf, func(data []byte) {
+			msg := (*inetDiagMsg)(unsafe.Pointer(&data[0]))
+			var attr *syscall.RtAttr
+			for i := rtaAlign(int(unsafe.Sizeof(inetDiagMsg{}))); i+syscall.SizeofRtAttr <= len(data); i += rtaAlign(int(attr.Len)) {
+				attr = (*syscall.RtAttr)(unsafe.Pointer(&data[i]))
+				if attr.Len < syscall.SizeofRtAttr || i+int(attr.Len) > len(data) {
+					break
+				}
+				if attr.Type == inetDiagIinfo {
+					var info unix.TCPInfo // older kernels report a shorter tcp_info
+					copy((*[unsafe.Sizeof(info)]byte)(unsafe.Pointer(&info))[:], data[i+syscall.SizeofRtAttr:i+int(attr.Len)])
+					sb[msg.idiagInode] = [2]uint64{info.Bytes_acked, info.Bytes_received}
+				}
+			}
+		}); err != nil {
+			return sb, err
+		}
+	}
+
+	return sb, nil
+}
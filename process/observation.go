@@ -3,6 +3,8 @@
 package process
 
 import (
+	"time"
+
 	"github.com/zosmac/gocore"
 	"github.com/zosmac/gomon/message"
 )
@@ -15,11 +17,31 @@ type (
 	// processEvent type.
 	processEvent string
 
+	// Details contains the properties of an observed process, captured at its fork or exec.
+	Details struct {
+		Ppid        Pid      `json:"ppid" gomon:"property"`
+		Ancestors   []string `json:"ancestors,omitempty" gomon:"property"` // names of parent chain, eldest first
+		Uid         int      `json:"uid" gomon:"property,,!windows"`
+		Gid         int      `json:"gid" gomon:"property,,!windows"`
+		Username    string   `json:"username,omitempty" gomon:"property"`
+		Groupname   string   `json:"groupname,omitempty" gomon:"property,,!windows"`
+		CommandLine `gomon:""`
+		Cwd         string `json:"cwd,omitempty" gomon:"property"`
+	}
+
 	// Observation defines the properties of a process message.
 	Observation struct {
 		message.Header[processEvent] `gomon:""`
 		EventID                      `json:"event_id" gomon:""`
 		Message                      string `json:"message" gomon:"property"`
+		Details                      `gomon:""`
+		ExitStatus                   *int          `json:"exit_status,omitempty" gomon:"property,,linux"` // unset if unknown
+		ExitSignal                   int           `json:"exit_signal,omitempty" gomon:"property,,linux"`
+		Duration                     time.Duration `json:"duration,omitempty" gomon:"gauge,ns"` // since fork, reported at exit
+		Total                        time.Duration `json:"total,omitempty" gomon:"counter,ns"`  // final metrics, reported at disappearance
+		Resident                     int           `json:"resident,omitempty" gomon:"gauge,B"`
+		ReadActual                   int           `json:"read_actual,omitempty" gomon:"counter,B"`
+		WriteActual                  int           `json:"write_actual,omitempty" gomon:"counter,B"`
 	}
 )
 
@@ -30,6 +52,10 @@ const (
 	processExit   processEvent = "exit"
 	processSetuid processEvent = "setuid" // linux only
 	processSetgid processEvent = "setgid" // linux only
+
+	// fallback message events derived from process table differences.
+	processAppeared    processEvent = "appeared"
+	processDisappeared processEvent = "disappeared"
 )
 
 var (
@@ -40,6 +66,8 @@ var (
 		processExit,
 		processSetuid,
 		processSetgid,
+		processAppeared,
+		processDisappeared,
 	)
 )
 
@@ -12,6 +12,7 @@ import (
 
 func init() {
 	message.Define(&tsMeasurement{})
+	message.Define(&exitSummary{})
 }
 
 type (
@@ -87,11 +88,31 @@ type (
 		Uname string `json:"uname" gomon:"property"`
 		Gname string `json:"gname" gomon:"property"`
 	}
+
+	// exitSummary reports the command line and lifetime resource usage of an exited process,
+	// accumulated from the exit-time taskstats of its threads.
+	exitSummary struct {
+		message.Header[netlinkEvent] `gomon:""`
+		EventID                      `json:"event_id" gomon:""`
+		CommandLine                  `gomon:""`
+		Uname                        string        `json:"uname" gomon:"property"`
+		ExitStatus                   int           `json:"exit_status" gomon:"property"`
+		ExitSignal                   int           `json:"exit_signal,omitempty" gomon:"property"`
+		Threads                      int           `json:"threads" gomon:"gauge,count"`
+		Lifetime                     time.Duration `json:"lifetime" gomon:"gauge,ns"`
+		User                         time.Duration `json:"user" gomon:"counter,ns"`
+		System                       time.Duration `json:"system" gomon:"counter,ns"`
+		Total                        time.Duration `json:"total" gomon:"counter,ns"`
+		ReadActual                   int           `json:"read_actual" gomon:"counter,B"`
+		WriteActual                  int           `json:"write_actual" gomon:"counter,B"`
+		ResidentMemoryMax            int           `json:"resident_memory_max" gomon:"gauge,B"`
+	}
 )
 
 const (
 	// message events.
 	netlinkTaskstats netlinkEvent = "taskstats"
+	netlinkExited    netlinkEvent = "exited"
 )
 
 var (
@@ -99,6 +120,11 @@ var (
 	netlinkEvents = gocore.ValidValue[netlinkEvent]{}.Define(
 		netlinkTaskstats,
 	)
+
+	// summaryEvents valid event values for exit summary messages.
+	summaryEvents = gocore.ValidValue[netlinkEvent]{}.Define(
+		netlinkExited,
+	)
 )
 
 // Events returns the list of acceptable Event values for this message.
@@ -110,3 +136,20 @@ func (*tsMeasurement) Events() []string {
 func (m *tsMeasurement) ID() string {
 	return m.EventID.Name + "[" + m.EventID.Pid.String() + "]"
 }
+
+// Events returns the list of acceptable Event values for this message.
+func (*exitSummary) Events() []string {
+	return summaryEvents.ValidValues()
+}
+
+// ID returns the identifier for an exit summary message.
+func (m *exitSummary) ID() string {
+	return m.EventID.Name + "[" + m.EventID.Pid.String() + "]"
+}
+
+// WithoutEnvs returns a copy of the exit summary without the process environment.
+func (m *exitSummary) WithoutEnvs() message.Content {
+	c := *m
+	c.Envs = nil
+	return &c
+}
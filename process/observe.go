@@ -4,56 +4,231 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/zosmac/gocore"
 	"github.com/zosmac/gomon/message"
 )
 
 var (
 	// messageChan queues process event observations for periodic reporting.
 	messageChan = make(chan *Observation, 100)
+
+	// details caches the properties of observed processes to report with their later observations.
+	// Only the goroutine reporting observations accesses it.
+	details = map[Pid]Details{}
+
+	// fallback indicates that process observations derive from differences between process table snapshots.
+	fallback atomic.Bool
+
+	// overflow indicates that observations are being dropped because messageChan is full.
+	overflow atomic.Bool
+
+	// opened records that Open attempted to open the process event source, and openErr its result.
+	opened  bool
+	openErr error
 )
 
-// Observer starts capture of process event observations.
+// openEvents opens the process event source, which may require root authority.
+func openEvents() {
+	opened = true
+	openErr = open()
+}
+
+// Observer starts capture of process event observations, from the event source opened by Open if called.
 func Observer(ctx context.Context) error {
-	if err := open(); err != nil {
-		return err
+	if !opened {
+		openEvents()
 	}
-
-	if err := observe(); err != nil {
+	if err := openErr; err != nil {
+		gocore.Error("process events unavailable, observing process table differences", err).Warn()
+		fallback.Store(true)
+	} else if err := observe(); err != nil {
 		return err
 	}
 
 	go func() {
 		for obs := range messageChan {
-			message.Observations([]message.Content{obs})
+			if obs.resolve() {
+				message.Observations([]message.Content{obs})
+			}
 		}
 	}()
 
 	return nil
 }
 
+// Fallback reports whether process observations derive from differences between the process tables
+// of successive measurements, which requires that the process or system measurement is selected.
+func Fallback() bool {
+	return fallback.Load()
+}
+
 // notify assembles a message and queues it.
-func notify(id *EventID, ev processEvent, msg string) {
-	messageChan <- &Observation{
-		Header:  message.Observation(time.Now(), ev),
-		EventID: *id,
-		Message: msg,
+func notify(obs *Observation, ev processEvent, msg string) {
+	obs.Header = message.Observation(time.Now(), ev)
+	obs.Message = msg
+	queue(obs)
+}
+
+// forget queues the release of the details of a process whose exit is not reported.
+func forget(pid Pid) {
+	queue(&Observation{EventID: EventID{Pid: pid}})
+}
+
+// queue queues an observation without blocking the receipt of process events or the measurement
+// of processes. If the queue is full, the observation is dropped.
+func queue(obs *Observation) {
+	select {
+	case messageChan <- obs:
+		if overflow.CompareAndSwap(true, false) {
+			gocore.Error("process observations", nil, map[string]string{
+				"queue": "recovered",
+			}).Info()
+		}
+	default:
+		if overflow.CompareAndSwap(false, true) {
+			gocore.Error("process observations", errors.New("queue full, dropping observations")).Warn()
+		}
 	}
 }
 
+// resolve completes an observation with the details of its process before it is reported, away from the
+// receipt of process events. The details are captured at fork or exec and retained for the process'
+// later observations until it exits. An observation without an event only releases the details.
+func (obs *Observation) resolve() bool {
+	switch obs.Event {
+	case "":
+		delete(details, obs.Pid)
+		return false
+	case processFork, processExec:
+		obs.Details = obs.EventID.details()
+		details[obs.Pid] = obs.Details
+	case processSetuid, processSetgid:
+		d, ok := details[obs.Pid]
+		if !ok {
+			d = Details{Ppid: obs.ppid}
+		}
+		if obs.Event == processSetuid {
+			d.Uid, d.Username = obs.Uid, obs.Username
+		} else {
+			d.Gid, d.Groupname = obs.Gid, obs.Groupname
+		}
+		obs.Details = d
+		details[obs.Pid] = d
+	case processExit:
+		if d, ok := details[obs.Pid]; ok {
+			obs.Details = d
+		}
+		delete(details, obs.Pid)
+	}
+	return true
+}
+
 // fork reports a process fork.
 func (id *EventID) fork() {
-	notify(id, processFork, fmt.Sprintf("%s[%d] -> [%d:%s]", id.Name, id.ppid, id.Pid, id.Starttime.Format("20060102-150405")))
+	notify(
+		&Observation{EventID: *id},
+		processFork,
+		fmt.Sprintf("%s[%d] -> [%d:%s]", id.Name, id.ppid, id.Pid, id.Starttime.Format("20060102-150405")),
+	)
 }
 
 // exec reports a process exec.
 func (id *EventID) exec() {
-	notify(id, processExec, fmt.Sprintf("[%d] -> %s[%d:%s]", id.ppid, id.Name, id.Pid, id.Starttime.Format("20060102-150405")))
+	notify(
+		&Observation{EventID: *id},
+		processExec,
+		fmt.Sprintf("[%d] -> %s[%d:%s]", id.ppid, id.Name, id.Pid, id.Starttime.Format("20060102-150405")),
+	)
+}
+
+// exit reports a process exit with its wait status, if known.
+func (id *EventID) exit(ws *syscall.WaitStatus) {
+	obs := &Observation{EventID: *id, Details: Details{Ppid: id.ppid}}
+	if !id.Starttime.IsZero() {
+		obs.Duration = time.Since(id.Starttime)
+	}
+	if ws != nil {
+		if ws.Signaled() {
+			obs.ExitSignal = int(ws.Signal())
+		} else {
+			status := ws.ExitStatus()
+			obs.ExitStatus = &status
+		}
+	}
+	notify(
+		obs,
+		processExit,
+		fmt.Sprintf("%s[%d:%s]", id.Name, id.Pid, id.Starttime.Format("20060102-150405")),
+	)
+}
+
+// appeared reports a process new to the process table, in lieu of fork and exec events.
+func (p *Process) appeared() {
+	notify(
+		&Observation{
+			EventID: p.EventID,
+			Details: Details{
+				Ppid:        p.Ppid,
+				Ancestors:   ancestors(p.Ppid, Pid.parent),
+				Uid:         p.Uid,
+				Gid:         p.Gid,
+				Username:    p.Username,
+				Groupname:   p.Groupname,
+				CommandLine: p.CommandLine,
+				Cwd:         p.Cwd,
+			},
+			Duration: time.Since(p.Starttime),
+		},
+		processAppeared,
+		fmt.Sprintf("[%d] -> %s[%d:%s]", p.Ppid, p.EventID.Name, p.Pid, p.Starttime.Format("20060102-150405")),
+	)
 }
 
-// exit reports a process exit.
-func (id *EventID) exit() {
-	notify(id, processExit, fmt.Sprintf("%s[%d:%s]", id.Name, id.Pid, id.Starttime.Format("20060102-150405")))
+// disappeared reports a process gone from the process table with its final metrics, in lieu of an exit event.
+func (p *Process) disappeared() {
+	notify(
+		&Observation{
+			EventID: p.EventID,
+			Details: Details{
+				Ppid:        p.Ppid,
+				Uid:         p.Uid,
+				Gid:         p.Gid,
+				Username:    p.Username,
+				Groupname:   p.Groupname,
+				CommandLine: p.CommandLine,
+				Cwd:         p.Cwd,
+			},
+			Duration:    time.Since(p.Starttime),
+			Total:       p.Total,
+			Resident:    p.Resident,
+			ReadActual:  p.ReadActual,
+			WriteActual: p.WriteActual,
+		},
+		processDisappeared,
+		fmt.Sprintf("%s[%d:%s]", p.EventID.Name, p.Pid, p.Starttime.Format("20060102-150405")),
+	)
+}
+
+// details captures the properties of a process to report with its observations.
+func (id *EventID) details() Details {
+	uid, gid := id.Pid.credentials()
+	d := Details{
+		Ppid:        id.ppid,
+		Ancestors:   ancestors(id.ppid, Pid.parent),
+		Uid:         uid,
+		Gid:         gid,
+		CommandLine: id.Pid.commandLine(),
+		Cwd:         id.Pid.directories().Cwd,
+	}
+	if uid >= 0 { // process may have exited
+		d.Username = gocore.Username(uid)
+		d.Groupname = gocore.Groupname(gid)
+	}
+	return d
 }
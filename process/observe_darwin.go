@@ -144,7 +144,7 @@ func observe() error {
 					delete(families[id.ppid], pid)
 					delete(families, pid)
 					id.Pid = pid
-					id.exit()
+					id.exit(nil) // exit status is only reported for children of the observer
 				}
 			}
 		}
@@ -182,6 +182,30 @@ func newKids(kd int, ppid Pid) {
 	}
 }
 
+// credentials gets the real user and group ids of a process.
+func (pid Pid) credentials() (int, int) {
+	var bsd C.struct_proc_bsdinfo
+	if n := C.proc_pidinfo(
+		C.int(pid),
+		C.PROC_PIDTBSDINFO,
+		0,
+		unsafe.Pointer(&bsd),
+		C.int(C.PROC_PIDTBSDINFO_SIZE),
+	); n != C.int(C.PROC_PIDTBSDINFO_SIZE) {
+		return -1, -1
+	}
+	return int(bsd.pbi_ruid), int(bsd.pbi_rgid)
+}
+
+// parent gets the name and parent of a process.
+func (pid Pid) parent() (string, Pid, bool) {
+	id, err := pid.id()
+	if err != nil {
+		return "", 0, false
+	}
+	return id.Name, id.ppid, true
+}
+
 // children identifies existing kids of parent.
 func children(ppid Pid) ids {
 	n := C.proc_listpids(C.PROC_PPID_ONLY, C.uint32_t(ppid), nil, 0)
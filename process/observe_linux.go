@@ -4,12 +4,17 @@ package process
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
 
 	"github.com/zosmac/gocore"
 	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
 	"golang.org/x/sys/unix"
 )
 
@@ -27,11 +32,20 @@ var (
 	h = handle{fd: -1, gd: -1, id: -1}
 
 	// ids maps pids to current process instances.
-	ids = map[Pid]EventID{}
+	ids    = map[Pid]EventID{}
+	idLock sync.Mutex
+
+	// exits accumulates the exit-time taskstats of each thread group until its process exits.
+	exits    = map[Pid]*exitSummary{}
+	exitLock sync.Mutex
 )
 
 // open obtains netlink socket descriptors.
 func open() error {
+	if !netAdmin() { // the kernel rejects process connector subscriptions without CAP_NET_ADMIN
+		return gocore.Error("nlProcess", syscall.EPERM)
+	}
+
 	// enable the netlink process connector
 	fd, err := nlProcess()
 	if err != nil {
@@ -102,6 +116,7 @@ func observe() error {
 				hdr := (*procEvent)(unsafe.Pointer(&m.Data[unsafe.Sizeof(cnMsg{})]))
 				ev := unsafe.Pointer(&m.Data[unsafe.Sizeof(cnMsg{})+unsafe.Sizeof(procEvent{})])
 
+				idLock.Lock()
 				switch hdr.what {
 				case procEventFork:
 					event := (*forkProcEvent)(ev)
@@ -110,6 +125,7 @@ func observe() error {
 					id := pid.id()
 					id.ppid = ppid // preserve in case child reassigned to init process
 					ids[id.Pid] = id
+					summarize(id)
 					id.fork()
 
 				case procEventExec:
@@ -120,6 +136,10 @@ func observe() error {
 						id.ppid = i.ppid // preserve in case child reassigned to init process
 					}
 					ids[pid] = id
+					clLock.Lock()
+					delete(clMap, pid) // replaced by exec
+					clLock.Unlock()
+					summarize(id)
 					id.exec()
 
 				case procEventExit:
@@ -127,7 +147,10 @@ func observe() error {
 					pid := Pid(event.processTgid)
 					if id, ok := ids[pid]; ok {
 						delete(ids, pid)
-						id.exit()
+						ws := syscall.WaitStatus(event.exitCode)
+						id.exit(&ws)
+					} else {
+						forget(pid)
 					}
 
 				case procEventUID:
@@ -162,6 +185,7 @@ func observe() error {
 					// 	fmt.Fprintf(os.Stderr, "COMM ========== %#v\n", event)
 					// }
 				}
+				idLock.Unlock()
 			}
 		}
 	}()
@@ -171,18 +195,57 @@ func observe() error {
 
 // setuid reports a process change uid. (linux only)
 func (id *EventID) setuid(uid int) {
-	notify(id, processSetuid, fmt.Sprintf("%s[%d] uid: %d", id.Name, id.Pid, uid))
+	notify(
+		&Observation{EventID: *id, Details: Details{Uid: uid, Username: gocore.Username(uid)}},
+		processSetuid,
+		fmt.Sprintf("%s[%d] uid: %d", id.Name, id.Pid, uid),
+	)
 }
 
 // setgid reports a process change gid. (linux only)
 func (id *EventID) setgid(gid int) {
-	notify(id, processSetgid, fmt.Sprintf("%s[%d] gid: %d", id.Name, id.Pid, gid))
+	notify(
+		&Observation{EventID: *id, Details: Details{Gid: gid, Groupname: gocore.Groupname(gid)}},
+		processSetgid,
+		fmt.Sprintf("%s[%d] gid: %d", id.Name, id.Pid, gid),
+	)
+}
+
+// credentials gets the real user and group ids of a process.
+func (pid Pid) credentials() (int, int) {
+	m, err := gocore.Measures(sysroot.Proc(pid.String(), "status"))
+	if err != nil {
+		return -1, -1
+	}
+	uid, _ := strconv.Atoi(m["Uid"])
+	gid, _ := strconv.Atoi(m["Gid"])
+	return uid, gid
+}
+
+// parent gets the name and parent of a process.
+func (pid Pid) parent() (string, Pid, bool) {
+	buf, err := os.ReadFile(sysroot.Proc(pid.String(), "stat"))
+	if err != nil {
+		return "", 0, false
+	}
+	stat := string(buf)
+	i := strings.IndexByte(stat, '(')
+	j := strings.LastIndexByte(stat, ')')
+	if i < 0 || j < i {
+		return "", 0, false
+	}
+	fields := strings.Fields(stat[j+1:]) // fields[1] is the ppid, i.e. stat field 4
+	if len(fields) < 2 {
+		return "", 0, false
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return stat[i+1 : j], Pid(ppid), true
 }
 
 // taskstats reads netlink process metrics.
 func taskstats() {
 	for {
-		nlMsg := make([]byte, 1024)
+		nlMsg := make([]byte, connectorMaxMessageSize)
 		n, _, err := syscall.Recvfrom(h.gd, nlMsg, 0)
 		if err != nil {
 			gocore.Error("Recvfrom", err).Err()
@@ -197,18 +260,12 @@ func taskstats() {
 			}
 			data := m.Data[unix.GENL_HDRLEN:]
 
-			var tsMsg *struct {
-				attr1 syscall.NlAttr
-				attr2 syscall.NlAttr
-				pid   uint32
-				attr3 syscall.NlAttr
-				ts    unix.Taskstats
-			}
+			var tsMsg *tsAggregate
 
 			for i := 0; i < len(data); i += nlaAlignTo(int(tsMsg.attr1.Len)) {
 				*(*uintptr)(unsafe.Pointer(&tsMsg)) = uintptr(unsafe.Pointer(&data[i]))
-				if tsMsg.attr1.Type != unix.TASKSTATS_TYPE_AGGR_PID && tsMsg.attr1.Type != unix.TASKSTATS_TYPE_AGGR_TGID &&
-					tsMsg.attr2.Type != unix.TASKSTATS_TYPE_PID && tsMsg.attr2.Type != unix.TASKSTATS_TYPE_TGID &&
+				if tsMsg.attr1.Type != unix.TASKSTATS_TYPE_AGGR_PID && tsMsg.attr1.Type != unix.TASKSTATS_TYPE_AGGR_TGID ||
+					tsMsg.attr2.Type != unix.TASKSTATS_TYPE_PID && tsMsg.attr2.Type != unix.TASKSTATS_TYPE_TGID ||
 					tsMsg.attr3.Type != unix.TASKSTATS_TYPE_STATS {
 					continue
 				}
@@ -236,7 +293,113 @@ func taskstats() {
 				ts.Gname = gocore.Groupname(int(ts.Ac_gid))
 
 				message.Measurements([]message.Content{&ts})
+
+				if tsMsg.attr1.Type == unix.TASKSTATS_TYPE_AGGR_TGID {
+					groupExit(Pid(tsMsg.pid))
+				} else {
+					threadExit(&tsMsg.ts)
+				}
 			}
 		}
 	}
 }
+
+// summarize snapshots the identity of a process at its fork or exec for its exit summary, retaining
+// the taskstats of any threads that have already exited.
+func summarize(id EventID) {
+	exitLock.Lock()
+	defer exitLock.Unlock()
+
+	sum, ok := exits[id.Pid]
+	if !ok || id.Starttime != sum.EventID.Starttime { // new process
+		sum = &exitSummary{}
+		exits[id.Pid] = sum
+	}
+	sum.EventID = id
+}
+
+// threadExit accumulates the exit-time taskstats of a thread into its process' summary.
+// A single threaded process exits with its thread group leader, so its summary is reported
+// now. Otherwise the summary awaits the thread group's exit.
+func threadExit(ts *unix.Taskstats) {
+	tgid := Pid(ts.Ac_tgid)
+
+	exitLock.Lock()
+	defer exitLock.Unlock()
+
+	sum, ok := exits[tgid]
+	if !ok {
+		sum = &exitSummary{}
+		exits[tgid] = sum
+	}
+
+	sum.Threads++
+	sum.User += time.Duration(ts.Ac_utime) * time.Microsecond
+	sum.System += time.Duration(ts.Ac_stime) * time.Microsecond
+	sum.Total = sum.User + sum.System
+	sum.ReadActual += int(ts.Read_bytes)
+	sum.WriteActual += int(ts.Write_bytes)
+	sum.ResidentMemoryMax = max(sum.ResidentMemoryMax, int(ts.Hiwater_rss)*1024)
+
+	if Pid(ts.Ac_pid) != tgid {
+		return
+	}
+
+	// the thread group leader
+	if sum.EventID.Pid == 0 { // started before the observer
+		sum.EventID = EventID{
+			ppid:      Pid(ts.Ac_ppid),
+			Name:      gocore.GoStringN((&ts.Ac_comm[0]), len(ts.Ac_comm)),
+			Pid:       tgid,
+			Starttime: time.Unix(int64(ts.Ac_btime), 0),
+		}
+	}
+	sum.Uname = gocore.Username(int(ts.Ac_uid))
+	sum.Lifetime = time.Duration(ts.Ac_etime) * time.Microsecond
+	if sig := int(ts.Ac_exitcode & 0x7f); sig != 0 {
+		sum.ExitSignal = sig
+	} else {
+		sum.ExitStatus = int(ts.Ac_exitcode>>8) & 0xff
+	}
+
+	// the kernel reports thread group taskstats for a process that had other threads
+	if sum.Threads == 1 && tgid.alone() {
+		delete(exits, tgid)
+		sum.report()
+	}
+}
+
+// groupExit reports the summary of a multithreaded process when its last thread exits.
+func groupExit(tgid Pid) {
+	exitLock.Lock()
+	sum, ok := exits[tgid]
+	delete(exits, tgid)
+	exitLock.Unlock()
+
+	if ok {
+		sum.report()
+	}
+}
+
+// report reports an exit summary with the process' command line.
+func (sum *exitSummary) report() {
+	sum.Header = message.Observation(time.Now(), netlinkExited)
+
+	clLock.Lock()
+	sum.CommandLine = clMap[sum.EventID.Pid]
+	delete(clMap, sum.EventID.Pid)
+	clLock.Unlock()
+
+	message.Observations([]message.Content{sum})
+}
+
+// alone determines whether a thread group leader has no other threads.
+func (pid Pid) alone() bool {
+	dir, err := os.Open(sysroot.Proc(pid.String(), "task"))
+	if err != nil {
+		return true // reaped
+	}
+	defer dir.Close()
+	ns, _ := dir.Readdirnames(2)
+	return len(ns) < 2
+}
@@ -27,6 +27,16 @@ func observe() error {
 	return gocore.Unsupported()
 }
 
+// credentials gets the user and group ids of a process. Unsupported on windows.
+func (pid Pid) credentials() (int, int) {
+	return -1, -1
+}
+
+// parent gets the name and parent of a process. Unsupported on windows.
+func (pid Pid) parent() (string, Pid, bool) {
+	return "", 0, false
+}
+
 // userGroup determines user name and group for a running process
 func (pid Pid) userGroup() (string, string, error) {
 	return "", "", gocore.Unsupported()
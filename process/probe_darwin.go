@@ -0,0 +1,19 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"os"
+
+	"github.com/zosmac/gomon/capability"
+)
+
+// Probe determines the availability of the process measurement and observation subsystems.
+func Probe() []capability.Capability {
+	c := capability.Capability{Name: "process_info", Mode: capability.Full}
+	if os.Geteuid() != 0 {
+		c.Mode = capability.Degraded
+		c.Detail = "details of other users' processes unreported"
+	}
+	return []capability.Capability{lsofProbe(), c}
+}
@@ -0,0 +1,74 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/capability"
+	"github.com/zosmac/gomon/sysroot"
+	"golang.org/x/sys/unix"
+)
+
+// Probe determines the availability of the process measurement and observation subsystems.
+func Probe() []capability.Capability {
+	cs := []capability.Capability{lsofProbe()}
+
+	// the process connector requires CAP_NET_ADMIN to receive fork, exec and exit events
+	c := capability.Capability{Name: "netlink_connector", Mode: capability.Full}
+	if h.fd >= 0 {
+		// the process observer opened the connector with root authority
+	} else if !netAdmin() {
+		c.Mode = capability.Degraded
+		c.Detail = "process observations derived from process table differences"
+	} else if fd, err := nlProcess(); err != nil {
+		c.Mode = capability.Degraded
+		c.Detail = "process observations derived from process table differences"
+	} else {
+		nlProcessObserve(fd, false)
+		syscall.Close(fd)
+	}
+	cs = append(cs, c)
+
+	c = capability.Capability{Name: "taskstats", Mode: capability.Full}
+	tqLock.Lock()
+	if tq.gd < 0 {
+		if err := openTaskstats(); err != nil {
+			c.Mode = capability.Degraded
+			c.Detail = "exit summaries and delays unreported"
+		}
+	}
+	if tq.gd >= 0 {
+		if _, err := nlTaskstats(tq.gd, tq.id, Pid(os.Getpid())); err != nil {
+			c.Mode = capability.Degraded
+			c.Detail = "delays from schedstat only"
+		}
+	}
+	tqLock.Unlock()
+	cs = append(cs, c)
+
+	// I/O accounting of other users' processes requires CAP_SYS_PTRACE
+	c = capability.Capability{Name: "process_io", Mode: capability.Full}
+	if os.Geteuid() != 0 {
+		if _, err := os.ReadFile(sysroot.Proc("1", "io")); err != nil {
+			c.Mode = capability.Degraded
+			c.Detail = "I/O of other users' processes unreported"
+		}
+	}
+	cs = append(cs, c)
+
+	return cs
+}
+
+// netAdmin reports whether gomon has the CAP_NET_ADMIN capability.
+func netAdmin() bool {
+	m, err := gocore.Measures("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	caps, err := strconv.ParseUint(m["CapEff"], 16, 64)
+	return err == nil && caps&(1<<unix.CAP_NET_ADMIN) != 0
+}
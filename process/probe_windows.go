@@ -0,0 +1,17 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"github.com/zosmac/gomon/capability"
+)
+
+// Probe determines the availability of the process measurement and observation subsystems.
+func Probe() []capability.Capability {
+	return []capability.Capability{{
+		Name:         "connections",
+		Mode:         capability.Disabled,
+		Detail:       "process connections unsupported",
+		Measurements: []string{"listeners"},
+	}}
+}
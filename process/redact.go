@@ -0,0 +1,116 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"strings"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// redacted replaces the value of a secret.
+	redacted = "[REDACTED]"
+)
+
+// redact scrubs secrets from command line arguments and environment variables per the redaction flags.
+// Command lines are redacted when captured, so that no sink reports their secrets.
+func (cl CommandLine) redact() CommandLine {
+	if cl.Args != nil {
+		cl.Args = redactArgs(cl.Args)
+	}
+	if cl.Envs != nil {
+		cl.Envs = redactEnvs(cl.Envs)
+	}
+	return cl
+}
+
+// redactArgs redacts the values of secret options and scrubs the arguments of a command line.
+func redactArgs(ss []string) []string {
+	args := make([]string, len(ss))
+	for i := 0; i < len(ss); i++ {
+		arg := ss[i]
+		if strings.HasPrefix(arg, "-") {
+			if key, _, ok := strings.Cut(arg, "="); ok { // e.g. --password=value
+				if secret(key) {
+					args[i] = key + "=" + redacted
+					continue
+				}
+			} else if secret(arg) && i+1 < len(ss) && !strings.HasPrefix(ss[i+1], "-") { // e.g. --password value
+				args[i] = arg
+				i++
+				args[i] = redacted
+				continue
+			}
+		}
+		args[i] = scrub(arg)
+	}
+	return args
+}
+
+// redactEnvs redacts the values of secret environment variables and scrubs the others.
+func redactEnvs(ss []string) []string {
+	envs := make([]string, len(ss))
+	for i, env := range ss {
+		if key, _, ok := strings.Cut(env, "="); ok && secret(key) {
+			envs[i] = key + "=" + redacted
+		} else {
+			envs[i] = scrub(env)
+		}
+	}
+	return envs
+}
+
+// secret reports whether an environment variable or command line option name denotes a secret.
+func secret(key string) bool {
+	return enabled(flags.redactKeys) && flags.redactKeys.MatchString(strings.TrimLeft(key, "-"))
+}
+
+// scrub replaces secrets matched by the redact flag's expression in a value.
+func scrub(s string) string {
+	if !enabled(flags.redact) {
+		return s
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range flags.redact.FindAllStringSubmatchIndex(s, -1) {
+		i, j := m[0], m[1]
+		if len(m) > 3 && m[2] >= 0 { // redact only the subexpression
+			i, j = m[2], m[3]
+		}
+		sb.WriteString(s[last:i])
+		sb.WriteString(redacted)
+		last = j
+	}
+	if last == 0 {
+		return s
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// Redact scrubs secrets matched by the redact flag's expression from a value reported outside of process
+// messages, such as a connection endpoint or executable path shown in the nodegraph.
+func Redact(s string) string {
+	return scrub(s)
+}
+
+// enabled reports whether a redaction expression is set.
+func enabled(r gocore.Regexp) bool {
+	return r.Regexp != nil && r.String() != ""
+}
+
+// WithoutEnvs returns a copy of the measurement without the process environment.
+func (m *Measurement) WithoutEnvs() message.Content {
+	c := *m
+	c.Envs = nil
+	return &c
+}
+
+// WithoutEnvs returns a copy of the observation without the process environment.
+func (obs *Observation) WithoutEnvs() message.Content {
+	c := *obs
+	c.Envs = nil
+	return &c
+}
@@ -0,0 +1,175 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+func init() {
+	message.Define(&Thread{})
+}
+
+type (
+	// ThreadID identifies a thread of a process.
+	ThreadID struct {
+		Tid        Pid    `json:"tid" gomon:"property"`
+		ThreadName string `json:"thread_name" gomon:"property"`
+	}
+
+	// ThreadProperties defines thread measurement properties.
+	ThreadProperties struct {
+		Status string `json:"status" gomon:"enum,none"`
+	}
+
+	// ThreadMetrics defines thread measurement metrics.
+	ThreadMetrics struct {
+		Priority                    int           `json:"priority" gomon:"gauge,none"`
+		Nice                        int           `json:"nice" gomon:"gauge,none"`
+		Processor                   int           `json:"processor" gomon:"gauge,none"`
+		User                        time.Duration `json:"user" gomon:"counter,ns"`
+		System                      time.Duration `json:"system" gomon:"counter,ns"`
+		Total                       time.Duration `json:"total" gomon:"counter,ns"`
+		Runtime                     time.Duration `json:"runtime" gomon:"counter,ns"`
+		Migrations                  int           `json:"migrations" gomon:"counter,count"`
+		VoluntaryContextSwitches    int           `json:"voluntary_context_switches" gomon:"counter,count"`
+		NonVoluntaryContextSwitches int           `json:"nonvoluntary_context_switches" gomon:"counter,count"`
+		ContextSwitches             int           `json:"context_switches" gomon:"counter,count"`
+	}
+
+	// Thread defines the properties and metrics of a thread measurement of a watched process.
+	Thread struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""` // of the thread's process
+		ThreadID                             `json:"thread_id" gomon:""`
+		ThreadProperties                     `gomon:""`
+		ThreadMetrics                        `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Thread) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a thread message.
+func (m *Thread) ID() string {
+	return m.EventID.Name + "[" + m.EventID.Pid.String() + ":" + m.ThreadID.Tid.String() + "]"
+}
+
+// tasks captures the measurements of each thread of a process.
+func (p *Process) tasks() []message.Content {
+	dirname := sysroot.Proc(p.Pid.String(), "task")
+	dir, err := os.Open(dirname)
+	if err != nil {
+		gocore.Error("Open", err, map[string]string{"dir": dirname}).Err()
+		return nil
+	}
+	ns, err := dir.Readdirnames(0)
+	dir.Close()
+	if err != nil {
+		gocore.Error("Readdirnames", err, map[string]string{"dir": dirname}).Err()
+		return nil
+	}
+
+	var ms []message.Content
+	for _, n := range ns {
+		tid, err := strconv.Atoi(n)
+		if err != nil {
+			continue
+		}
+		if t := p.task(Pid(tid)); t != nil {
+			ms = append(ms, t)
+		}
+	}
+
+	return ms
+}
+
+// task captures the measurement of a thread from its stat, status, and sched files.
+func (p *Process) task(tid Pid) *Thread {
+	dirname := sysroot.Proc(p.Pid.String(), "task", tid.String())
+	buf, err := os.ReadFile(filepath.Join(dirname, "stat"))
+	if err != nil {
+		return nil // thread exited
+	}
+
+	// the thread name may contain spaces and parentheses, so locate its final closing parenthesis
+	stat := string(buf)
+	i := strings.IndexByte(stat, '(')
+	j := strings.LastIndexByte(stat, ')')
+	if i < 0 || j < i {
+		return nil
+	}
+	fields := strings.Fields(stat[j+1:]) // fields[0] is the state, i.e. stat field 3
+	if len(fields) < 37 {
+		return nil
+	}
+
+	user, _ := strconv.Atoi(fields[11])
+	system, _ := strconv.Atoi(fields[12])
+	priority, _ := strconv.Atoi(fields[15])
+	nice, _ := strconv.Atoi(fields[16])
+	processor, _ := strconv.Atoi(fields[36])
+
+	m, _ := gocore.Measures(filepath.Join(dirname, "status"))
+	voluntaryContextSwitches, _ := strconv.Atoi(m["voluntary_ctxt_switches"])
+	nonVoluntaryContextSwitches, _ := strconv.Atoi(m["nonvoluntary_ctxt_switches"])
+
+	s := schedMeasures(filepath.Join(dirname, "sched"))
+	runtime, _ := strconv.ParseFloat(s["se.sum_exec_runtime"], 64) // milliseconds
+	migrations, _ := strconv.Atoi(s["se.nr_migrations"])
+
+	return &Thread{
+		Header:  message.Measurement(),
+		EventID: p.EventID,
+		ThreadID: ThreadID{
+			Tid:        tid,
+			ThreadName: stat[i+1 : j],
+		},
+		ThreadProperties: ThreadProperties{
+			Status: status[fields[0][0]],
+		},
+		ThreadMetrics: ThreadMetrics{
+			Priority:                    priority,
+			Nice:                        nice,
+			Processor:                   processor,
+			User:                        time.Duration(user) * factor,
+			System:                      time.Duration(system) * factor,
+			Total:                       time.Duration(user+system) * factor,
+			Runtime:                     time.Duration(runtime * float64(time.Millisecond)),
+			Migrations:                  migrations,
+			VoluntaryContextSwitches:    voluntaryContextSwitches,
+			NonVoluntaryContextSwitches: nonVoluntaryContextSwitches,
+			ContextSwitches:             voluntaryContextSwitches + nonVoluntaryContextSwitches,
+		},
+	}
+}
+
+// schedMeasures reads a /proc sched file, whose names are padded for alignment, and produces a map of name:value pairs.
+func schedMeasures(filename string) map[string]string {
+	m := map[string]string{}
+	f, err := os.Open(filename)
+	if err != nil {
+		return m
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), ":"); ok {
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+
+	return m
+}
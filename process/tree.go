@@ -0,0 +1,118 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// Rollup sums resource usage of a process and its descendants.
+	Rollup struct {
+		Total       time.Duration `json:"total"`
+		Resident    int           `json:"resident"`
+		ReadActual  int           `json:"read_actual"`
+		WriteActual int           `json:"write_actual"`
+	}
+
+	// Node represents a process in the process hierarchy.
+	Node struct {
+		EventID    `json:"event_id"`
+		Ppid       Pid           `json:"ppid"`
+		Executable string        `json:"executable,omitempty"`
+		Username   string        `json:"username,omitempty"`
+		Total      time.Duration `json:"total"`
+		Resident   int           `json:"resident"`
+		Rollup     *Rollup       `json:"rollup,omitempty"`
+		Children   []*Node       `json:"children,omitempty"`
+	}
+)
+
+// Hierarchy returns the process hierarchy from the current process table, rooted at the process
+// of pid if specified, and optionally with resource usage summed over each process' descendants.
+func Hierarchy(pid Pid, rollup bool) []*Node {
+	procLock.RLock()
+	tb := procs
+	procLock.RUnlock()
+
+	tr := tb.BuildTree()
+	if pid > 0 {
+		if tr = tr.FindTree(pid); tr == nil {
+			return nil
+		}
+		tr = Tree{pid: tr[pid]}
+	}
+
+	return hierarchy(tb, tr, rollup)
+}
+
+// hierarchy builds the nodes of a level of the process tree.
+func hierarchy(tb Table, tr Tree, rollup bool) []*Node {
+	ns := make([]*Node, 0, len(tr))
+	for pid, tr := range tr {
+		p, ok := tb[pid]
+		if !ok {
+			continue
+		}
+		n := &Node{
+			EventID:    p.EventID,
+			Ppid:       p.Ppid,
+			Executable: p.Executable,
+			Username:   p.Username,
+			Total:      p.Total,
+			Resident:   p.Resident,
+			Children:   hierarchy(tb, tr, rollup),
+		}
+		if rollup {
+			n.Rollup = &Rollup{
+				Total:       p.Total,
+				Resident:    p.Resident,
+				ReadActual:  p.ReadActual,
+				WriteActual: p.WriteActual,
+			}
+			for _, c := range n.Children {
+				n.Rollup.Total += c.Rollup.Total
+				n.Rollup.Resident += c.Rollup.Resident
+				n.Rollup.ReadActual += c.Rollup.ReadActual
+				n.Rollup.WriteActual += c.Rollup.WriteActual
+			}
+		}
+		ns = append(ns, n)
+	}
+	slices.SortFunc(ns, func(a, b *Node) int {
+		return cmp.Compare(a.Pid, b.Pid)
+	})
+	return ns
+}
+
+// ancestors lists the names of a process' parent chain, eldest first, looking up each ancestor's name and parent with parent.
+func ancestors(ppid Pid, parent func(Pid) (string, Pid, bool)) []string {
+	var names []string
+	for ppid > 0 && len(names) < 64 { // guard against a cycle from pid reuse
+		name, pp, ok := parent(ppid)
+		if !ok {
+			break
+		}
+		names = append(names, name)
+		ppid = pp
+	}
+	slices.Reverse(names)
+	return names
+}
+
+// ancestry formats the chain of names from a process' eldest ancestor to the process, finding the
+// ancestors in the process table's tree.
+func ancestry(tb Table, tr Tree, pid Pid) string {
+	p, ok := tb[pid]
+	if !ok {
+		return ""
+	}
+	var names []string
+	for _, pid := range tr.Ancestors(pid) {
+		names = append(names, tb[pid].EventID.Name)
+	}
+	return strings.Join(append(names, p.EventID.Name), " > ")
+}
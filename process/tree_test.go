@@ -0,0 +1,116 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package process
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// table records a process table of init, a shell, and the shell's two commands.
+func table() Table {
+	tb := Table{}
+	for _, p := range []struct {
+		pid, ppid Pid
+		name      string
+		total     time.Duration
+		resident  int
+		read      int
+	}{
+		{1, 0, "systemd", 5 * time.Second, 12 << 20, 1 << 20},
+		{100, 1, "bash", time.Second, 4 << 20, 4096},
+		{200, 100, "make", 2 * time.Second, 8 << 20, 8192},
+		{201, 100, "sleep", 0, 1 << 20, 0},
+	} {
+		m := &Process{EventID: EventID{Name: p.name, Pid: p.pid}}
+		m.Ppid = p.ppid
+		m.Total = p.total
+		m.Resident = p.resident
+		m.ReadActual = p.read
+		tb[p.pid] = m
+	}
+	return tb
+}
+
+func TestHierarchy(t *testing.T) {
+	procLock.Lock()
+	saved := procs
+	procs = table()
+	procLock.Unlock()
+	defer func() {
+		procLock.Lock()
+		procs = saved
+		procLock.Unlock()
+	}()
+
+	ns := Hierarchy(0, true)
+	if len(ns) != 1 || ns[0].Pid != 1 {
+		t.Fatalf("Hierarchy() = %+v", ns)
+	}
+	if want := (Rollup{Total: 8 * time.Second, Resident: 25 << 20, ReadActual: 1<<20 + 4096 + 8192}); *ns[0].Rollup != want {
+		t.Errorf("Hierarchy() rollup = %+v, want %+v", *ns[0].Rollup, want)
+	}
+
+	ns = Hierarchy(100, true)
+	if len(ns) != 1 || ns[0].Pid != 100 || ns[0].Ppid != 1 || len(ns[0].Children) != 2 {
+		t.Fatalf("Hierarchy(100) = %+v", ns)
+	}
+	if pids := []Pid{ns[0].Children[0].Pid, ns[0].Children[1].Pid}; !slices.Equal(pids, []Pid{200, 201}) {
+		t.Errorf("Hierarchy(100) children = %v, want ordered by pid", pids)
+	}
+	if want := (Rollup{Total: 3 * time.Second, Resident: 13 << 20, ReadActual: 4096 + 8192}); *ns[0].Rollup != want {
+		t.Errorf("Hierarchy(100) rollup = %+v, want %+v", *ns[0].Rollup, want)
+	}
+
+	if ns = Hierarchy(100, false); ns[0].Rollup != nil {
+		t.Errorf("Hierarchy() without rollup = %+v", ns[0].Rollup)
+	}
+	if ns = Hierarchy(999, true); ns != nil {
+		t.Errorf("Hierarchy() of unknown process = %+v", ns)
+	}
+}
+
+func TestAncestry(t *testing.T) {
+	tb := table()
+	tr := tb.BuildTree()
+	tests := []struct {
+		pid  Pid
+		want string
+	}{
+		{1, "systemd"},
+		{100, "systemd > bash"},
+		{201, "systemd > bash > sleep"},
+		{999, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ancestry(tb, tr, tt.pid); got != tt.want {
+			t.Errorf("ancestry(%d) = %q, want %q", tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	tb := table()
+	parent := func(pid Pid) (string, Pid, bool) {
+		p, ok := tb[pid]
+		if !ok {
+			return "", 0, false
+		}
+		return p.EventID.Name, p.Ppid, true
+	}
+
+	if got, want := ancestors(100, parent), []string{"systemd", "bash"}; !slices.Equal(got, want) {
+		t.Errorf("ancestors() = %v, want %v", got, want)
+	}
+	if got := ancestors(999, parent); got != nil {
+		t.Errorf("ancestors() of exited parent = %v", got)
+	}
+
+	// a cycle from pid reuse is cut short
+	tb[1].Ppid = 201
+	if got := ancestors(201, parent); len(got) != 64 {
+		t.Errorf("ancestors() of cycle = %d names, want 64", len(got))
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package sensors measures the hardware sensors' temperatures, fan speeds, voltages and power for
+the "gomon" command, and observes sensors crossing their warning and critical thresholds.
+
+For Linux, the sensors are those of the thermal zones (/sys/class/thermal/thermal_zone*) and
+of the hardware monitoring chips (/sys/class/hwmon/hwmon*). Temperatures are reported in degrees
+Celsius, fan speeds in revolutions per minute, voltages in volts, and power in watts. A sensor is
+critical at or above its critical or emergency limit, or for fans and voltages, at or below its
+lower critical or minimum limit. A sensor at or above its maximum limit is reported as a warning.
+A power sensor's cap is a limit enforced by the hardware, not a threshold, and is not reported.
+
+Sensors are reported for Linux only.
+*/
+package sensors
@@ -0,0 +1,65 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"fmt"
+
+	"github.com/zosmac/gomon/message"
+)
+
+type (
+	// reading records the value of a sensor.
+	reading struct {
+		EventID
+		Properties
+		value float64
+	}
+)
+
+const (
+	// sensor types.
+	typeTemperature = "temperature"
+	typeFan         = "fan"
+	typeVoltage     = "voltage"
+	typePower       = "power"
+)
+
+// Measure captures the readings of the hardware sensors.
+func Measure() []message.Content {
+	rs := readings()
+	ms := make([]message.Content, 0, len(rs))
+	for _, r := range rs {
+		m := &Measurement{
+			Header:     message.Measurement(),
+			EventID:    r.EventID,
+			Properties: r.Properties,
+		}
+		switch r.Type {
+		case typeTemperature:
+			m.Temperature = r.value
+		case typeFan:
+			m.Fan = r.value
+		case typeVoltage:
+			m.Voltage = r.value
+		case typePower:
+			m.Power = r.value
+		}
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// threshold reports whether a reading has reached its critical threshold or fallen to its minimum,
+// or has reached its warning threshold, describing the crossing.
+func (r reading) threshold() (sensorEvent, string) {
+	switch {
+	case r.Critical > 0 && r.value >= r.Critical:
+		return sensorCritical, fmt.Sprintf("at or above critical %g", r.Critical)
+	case r.Minimum > 0 && r.value <= r.Minimum:
+		return sensorCritical, fmt.Sprintf("at or below minimum %g", r.Minimum)
+	case r.Warning > 0 && r.value >= r.Warning:
+		return sensorWarning, fmt.Sprintf("at or above warning %g", r.Warning)
+	}
+	return sensorRecovered, "within thresholds"
+}
@@ -0,0 +1,129 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zosmac/gomon/sysroot"
+)
+
+var (
+	// hwmonTypes maps hwmon sensor file prefixes to sensor types and the scale of their values.
+	hwmonTypes = map[string]struct {
+		kind    string
+		scale   float64
+		minimum bool // sensor fails at its low limit
+	}{
+		"temp":  {typeTemperature, 1000, false}, // millidegrees Celsius
+		"fan":   {typeFan, 1, true},             // RPM
+		"in":    {typeVoltage, 1000, true},      // millivolts
+		"power": {typePower, 1000000, false},    // microwatts
+	}
+)
+
+// readings reads the thermal zones and hwmon chips' sensors.
+func readings() []reading {
+	return append(thermalZones(), hwmons()...)
+}
+
+// thermalZones reads the temperatures of the thermal zones and their critical trip points.
+func thermalZones() []reading {
+	zones, _ := filepath.Glob(sysroot.Sys("class", "thermal", "thermal_zone[0-9]*"))
+	var rs []reading
+	for _, zone := range zones {
+		temp, ok := readFloat(filepath.Join(zone, "temp"))
+		if !ok {
+			continue
+		}
+		r := reading{
+			EventID: EventID{Sensor: filepath.Base(zone)},
+			Properties: Properties{
+				Type:  typeTemperature,
+				Label: readString(filepath.Join(zone, "type")),
+			},
+			value: temp / 1000,
+		}
+		trips, _ := filepath.Glob(filepath.Join(zone, "trip_point_[0-9]*_type"))
+		for _, trip := range trips {
+			if readString(trip) == "critical" {
+				if crit, ok := readFloat(strings.TrimSuffix(trip, "_type") + "_temp"); ok {
+					r.Critical = crit / 1000
+				}
+			}
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// hwmons reads the sensors of the hardware monitoring chips.
+func hwmons() []reading {
+	chips, _ := filepath.Glob(sysroot.Sys("class", "hwmon", "hwmon[0-9]*"))
+	var rs []reading
+	for _, chip := range chips {
+		name := readString(filepath.Join(chip, "name"))
+		inputs, _ := filepath.Glob(filepath.Join(chip, "*_input"))
+		slices.Sort(inputs)
+		for _, input := range inputs {
+			sensor := strings.TrimSuffix(filepath.Base(input), "_input") // e.g. temp1
+			prefix := strings.TrimRight(sensor, "0123456789")
+			t, ok := hwmonTypes[prefix]
+			if !ok {
+				continue
+			}
+			value, ok := readFloat(input)
+			if !ok {
+				continue
+			}
+			base := filepath.Join(chip, sensor)
+			r := reading{
+				EventID: EventID{Sensor: filepath.Base(chip) + "/" + sensor},
+				Properties: Properties{
+					Type:  t.kind,
+					Chip:  name,
+					Label: readString(base + "_label"),
+				},
+				value: value / t.scale,
+			}
+			for _, limit := range []string{"_crit", "_emergency"} {
+				if crit, ok := readFloat(base + limit); ok && crit > 0 {
+					r.Critical = crit / t.scale
+					break
+				}
+			}
+			if warn, ok := readFloat(base + "_max"); ok && warn > 0 {
+				r.Warning = warn / t.scale
+			}
+			if t.minimum {
+				for _, limit := range []string{"_lcrit", "_min"} {
+					if low, ok := readFloat(base + limit); ok && low > 0 {
+						r.Minimum = low / t.scale
+						break
+					}
+				}
+			}
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// readString reads a single value sysfs file.
+func readString(filename string) string {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// readFloat reads a single numeric value sysfs file.
+func readFloat(filename string) (float64, bool) {
+	f, err := strconv.ParseFloat(readString(filename), 64)
+	return f, err == nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("sysfs", "testdata/sys")
+	os.Exit(m.Run())
+}
+
+func TestHwmons(t *testing.T) {
+	want := map[string]struct {
+		value, critical, warning, minimum float64
+		event                             sensorEvent
+	}{
+		"hwmon0/fan1":   {0, 0, 0, 300, sensorCritical},        // stalled fan
+		"hwmon0/fan2":   {1200, 0, 0, 0, sensorRecovered},      // unset minimum
+		"hwmon0/in0":    {1.008, 0, 1.2, 0.9, sensorRecovered}, // lower critical preferred to minimum, maximum only a warning
+		"hwmon0/power1": {20, 0, 0, 0, sensorRecovered},        // cap is not a threshold
+		"hwmon0/temp1":  {45, 100, 0, 0, sensorRecovered},      // no minimum for temperatures
+		"hwmon0/temp2":  {85, 105, 80, 0, sensorWarning},       // emergency is critical, maximum a warning
+	}
+
+	rs := hwmons()
+	if len(rs) != len(want) {
+		t.Fatalf("hwmons() = %+v", rs)
+	}
+	for _, r := range rs {
+		w, ok := want[r.Sensor]
+		if !ok {
+			t.Errorf("hwmons() unexpected sensor %s", r.Sensor)
+			continue
+		}
+		if r.value != w.value || r.Critical != w.critical || r.Warning != w.warning || r.Minimum != w.minimum {
+			t.Errorf("%s = %g critical %g warning %g minimum %g, want %g critical %g warning %g minimum %g",
+				r.Sensor, r.value, r.Critical, r.Warning, r.Minimum, w.value, w.critical, w.warning, w.minimum)
+		}
+		if ev, msg := r.threshold(); ev != w.event {
+			t.Errorf("%s threshold() = %s %q, want %s", r.Sensor, ev, msg, w.event)
+		}
+	}
+}
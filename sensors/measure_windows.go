@@ -0,0 +1,8 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+// readings reads the hardware sensors. Not reported on this platform.
+func readings() []reading {
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Sensor string `json:"sensor" gomon:"property"` // e.g. thermal_zone0, hwmon1/temp2
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		Type     string  `json:"type" gomon:"property"` // temperature, fan, voltage, or power
+		Chip     string  `json:"chip,omitempty" gomon:"property"`
+		Label    string  `json:"label,omitempty" gomon:"property"`
+		Critical float64 `json:"critical,omitempty" gomon:"property"` // upper threshold
+		Warning  float64 `json:"warning,omitempty" gomon:"property"`  // upper warning threshold, below critical
+		Minimum  float64 `json:"minimum,omitempty" gomon:"property"`  // lower threshold of fans and voltages
+	}
+
+	// Metrics defines measurement metrics.
+	Metrics struct {
+		Temperature float64 `json:"temperature,omitempty" gomon:"gauge,C"`
+		Fan         float64 `json:"fan,omitempty" gomon:"gauge,rpm"`
+		Voltage     float64 `json:"voltage,omitempty" gomon:"gauge,V"`
+		Power       float64 `json:"power,omitempty" gomon:"gauge,W"`
+	}
+
+	// Measurement defines the properties and metrics of a sensor measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+		Metrics                              `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a sensor message.
+func (m *Measurement) ID() string {
+	return m.EventID.Sensor
+}
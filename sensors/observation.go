@@ -0,0 +1,52 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+)
+
+func init() {
+	message.Define(&Observation{})
+}
+
+type (
+	// sensorEvent type.
+	sensorEvent string
+
+	// Observation defines the properties of a sensor threshold crossing message.
+	Observation struct {
+		message.Header[sensorEvent] `gomon:""`
+		EventID                     `json:"event_id" gomon:""`
+		Properties                  `gomon:""`
+		Value                       float64 `json:"value" gomon:"property"`
+		Message                     string  `json:"message" gomon:"property"`
+	}
+)
+
+const (
+	// message events.
+	sensorCritical  sensorEvent = "critical"
+	sensorWarning   sensorEvent = "warning"
+	sensorRecovered sensorEvent = "recovered"
+)
+
+var (
+	// sensorEvents valid event values for messages.
+	sensorEvents = gocore.ValidValue[sensorEvent]{}.Define(
+		sensorCritical,
+		sensorWarning,
+		sensorRecovered,
+	)
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Observation) Events() []string {
+	return sensorEvents.ValidValues()
+}
+
+// ID returns the identifier for a sensor threshold crossing message.
+func (obs *Observation) ID() string {
+	return obs.EventID.Sensor
+}
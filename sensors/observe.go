@@ -0,0 +1,54 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sensors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// poll is the interval for reading sensors for threshold crossings.
+	poll = 5 * time.Second
+)
+
+// Observer starts capture of sensor warning and critical threshold crossing observations.
+func Observer(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		state := map[string]sensorEvent{}
+		for {
+			var obs []message.Content
+			for _, r := range readings() {
+				ev, msg := r.threshold()
+				prev, ok := state[r.Sensor]
+				state[r.Sensor] = ev
+				if ev != prev && (ok || ev != sensorRecovered) {
+					obs = append(obs, &Observation{
+						Header:     message.Observation(time.Now(), ev),
+						EventID:    r.EventID,
+						Properties: r.Properties,
+						Value:      r.value,
+						Message:    fmt.Sprintf("%s %s %g %s", r.Sensor, r.Type, r.value, msg),
+					})
+				}
+			}
+			if len(obs) > 0 {
+				message.Observations(obs)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
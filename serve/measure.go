@@ -4,31 +4,28 @@ package serve
 
 import (
 	"context"
-	"path"
-	"reflect"
 	"slices"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/capability"
+	"github.com/zosmac/gomon/clock"
 	"github.com/zosmac/gomon/filesystem"
+	"github.com/zosmac/gomon/interrupts"
+	"github.com/zosmac/gomon/inventory"
 	"github.com/zosmac/gomon/io"
 	"github.com/zosmac/gomon/message"
 	"github.com/zosmac/gomon/network"
+	"github.com/zosmac/gomon/numa"
 	"github.com/zosmac/gomon/process"
+	"github.com/zosmac/gomon/sensors"
 	"github.com/zosmac/gomon/system"
+	"github.com/zosmac/gomon/systemd"
 )
 
 func Measure(ctx context.Context, opts gocore.Options) error {
 
-	// start the process endpoints observer (i.e. lsof)
-	if slices.Contains(opts.Selected, "process") {
-		if err := process.Endpoints(ctx); err != nil {
-			return err
-		}
-	}
-
 	// capture and write with encoder
 	ticker := flags.sample.alignTicker()
 	for {
@@ -52,19 +49,7 @@ func Measure(ctx context.Context, opts gocore.Options) error {
 			count := measures.Collections
 			start := measures.CollectionTime
 			for _, m := range measure(opts) {
-				gocore.Format(
-					"gomon_"+path.Base(reflect.Indirect(reflect.ValueOf(m)).Type().PkgPath()),
-					"",
-					0,
-					reflect.ValueOf(m),
-					func(name, tag string, val reflect.Value) any {
-						if !strings.HasPrefix(tag, "property") {
-							ch <- prometheusMetric(m.ID(), name, tag, val)
-							measures.Collections++
-						}
-						return nil
-					},
-				)
+				measures.Collections += prometheusMetrics(ch, m)
 			}
 			gocore.Error("collect", nil, map[string]string{
 				"count": strconv.Itoa(measures.Collections - count),
@@ -96,6 +81,18 @@ func measure(opts gocore.Options) (ms []message.Content) {
 	}
 	ms = append(ms, sm)
 	ms = append(ms, pm...)
+	if slices.Contains(opts.Selected, "listeners") {
+		ms = append(ms, process.Listeners()...)
+	}
+	if slices.Contains(opts.Selected, "interrupts") {
+		ms = append(ms, interrupts.Measure()...)
+	}
+	if slices.Contains(opts.Selected, "clock") {
+		ms = append(ms, clock.Measure()...)
+	}
+	if slices.Contains(opts.Selected, "inventory") {
+		ms = append(ms, inventory.Measure()...)
+	}
 	if slices.Contains(opts.Selected, "io") {
 		ms = append(ms, io.Measure()...)
 	}
@@ -105,8 +102,18 @@ func measure(opts gocore.Options) (ms []message.Content) {
 	if slices.Contains(opts.Selected, "network") {
 		ms = append(ms, network.Measure()...)
 	}
+	if slices.Contains(opts.Selected, "numa") {
+		ms = append(ms, numa.Measure()...)
+	}
+	if slices.Contains(opts.Selected, "sensors") {
+		ms = append(ms, sensors.Measure()...)
+	}
+	if slices.Contains(opts.Selected, "systemd") {
+		ms = append(ms, systemd.Measure()...)
+	}
 
 	measures.Header.Timestamp = start
+	measures.Capabilities = capability.Report()
 	measures.CollectionTime += time.Since(start)
 	measures.LokiStreams += message.LokiStreams
 	ms = append(ms, &measures)
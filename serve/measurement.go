@@ -5,6 +5,7 @@ package serve
 import (
 	"time"
 
+	"github.com/zosmac/gomon/capability"
 	"github.com/zosmac/gomon/message"
 )
 
@@ -26,7 +27,8 @@ type (
 
 	// Properties defines measurement properties.
 	Properties struct {
-		WebServer `gomon:""`
+		WebServer    `gomon:""`
+		Capabilities []capability.Capability `json:"capabilities" gomon:"property"`
 	}
 
 	Prometheus struct {
@@ -229,13 +229,18 @@ func parseQuery(r *http.Request) *Query {
 
 func (query Query) HostNode(conn process.Connection) string {
 	host, port, _ := net.SplitHostPort(conn.Peer.Name)
+	class := ""
+	if conn.Class != "" {
+		class = `\n` + conn.Class
+	}
 	return fmt.Sprintf(
-		`[shape=cds style=filled fillcolor=%q height=0.6 width=2 label="%s:%s\n%s" tooltip=%q]`,
+		`[shape=cds style=filled fillcolor=%q height=0.6 width=2 label="%s:%s\n%s%s" tooltip=%q]`,
 		color(conn.Peer.Pid),
 		conn.Type,
 		port,
-		gocore.Hostname(host),
-		conn.Peer.Name,
+		process.Hostname(host),
+		class,
+		process.Redact(conn.Peer.Name),
 	)
 }
 
@@ -243,8 +248,8 @@ func (query Query) DataNode(conn process.Connection) string {
 	return fmt.Sprintf(
 		`[shape=note style=filled fillcolor=%q height=0.2 label=%q tooltip=%q]`,
 		color(conn.Peer.Pid),
-		conn.Type+":"+conn.Peer.Name,
-		conn.Peer.Name,
+		conn.Type+":"+process.Redact(conn.Peer.Name),
+		process.Redact(conn.Peer.Name),
 	)
 }
 
@@ -255,7 +260,7 @@ func (query Query) ProcNode(p *process.Process) string {
 		scheme,
 		flags.port,
 		p.EventID.Name,
-		p.Longname(),
+		process.Redact(p.Longname()),
 	)
 }
 
@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"path"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -16,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
 
 	"gopkg.in/yaml.v3"
 )
@@ -110,8 +115,103 @@ func (c *prometheusCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
-// prometheusMetric complies with Formatter function prototype for encoding metrics as Prometheus metrics.
-func prometheusMetric(id string, name, tag string, val reflect.Value) prometheus.Metric {
+// prometheusMetrics formats a message's metrics as Prometheus metrics. The name of each metric is qualified
+// by the names of the structures that enclose it. The metrics of slice elements that provide labels, such as
+// those of individual processors, are labeled rather than named by their index. Absent structures, which
+// gocore.Format walks as zero values, are skipped.
+func prometheusMetrics(ch chan<- prometheus.Metric, m message.Content) int {
+	var count int
+	var names []string             // of the enclosing structures by depth
+	var labels []map[string]string // of the enclosing structures by depth
+	var absent []map[string]bool   // nil pointer fields of the enclosing structures by depth
+	var skip int                   // depth of an absent structure being walked
+	gocore.Format(
+		"gomon_"+path.Base(reflect.Indirect(reflect.ValueOf(m)).Type().PkgPath()),
+		"",
+		0,
+		reflect.ValueOf(m),
+		func(name, tag string, val reflect.Value) any {
+			if len(names) == 0 { // the message
+				names = []string{name}
+				labels = []map[string]string{nil}
+				absent = []map[string]bool{nilPointers(val)}
+				return nil
+			}
+			depth := min(1+(len(name)-len(strings.TrimLeft(name, " ")))/2, len(names))
+			names, labels, absent = names[:depth], labels[:depth], absent[:depth]
+			name = gocore.SnakeCase(strings.TrimSpace(name))
+			if skip > 0 && depth <= skip {
+				skip = 0
+			}
+			if skip == 0 && absent[depth-1][name] {
+				skip = depth
+			}
+
+			if _, ok := val.Interface().(time.Time); !ok && val.Kind() == reflect.Struct {
+				var l map[string]string
+				if lb, ok := val.Interface().(interface{ Labels() map[string]string }); ok {
+					if i := strings.LastIndexByte(name, '_'); i > 0 {
+						if _, err := strconv.Atoi(name[i+1:]); err == nil { // a slice element
+							name = name[:i]
+							l = lb.Labels()
+						}
+					}
+				}
+				names = append(names, name)
+				labels = append(labels, l)
+				absent = append(absent, nilPointers(val))
+				return nil
+			}
+
+			if skip > 0 || strings.HasPrefix(tag, "property") {
+				return nil
+			}
+			ls := map[string]string{}
+			for _, l := range labels {
+				maps.Copy(ls, l)
+			}
+			ch <- prometheusMetric(m.ID(), strings.Join(append(names, name), "_"), tag, val, ls)
+			count++
+			return nil
+		},
+	)
+	return count
+}
+
+// nilPointers identifies by metric name the nil pointer fields of a structure, including those of its embedded structures.
+func nilPointers(val reflect.Value) map[string]bool {
+	val = reflect.Indirect(val)
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	var nils map[string]bool
+	t := val.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("gomon"); !ok {
+			continue
+		}
+		v := val.Field(i)
+		switch {
+		case f.Anonymous:
+			for n := range nilPointers(v) {
+				if nils == nil {
+					nils = map[string]bool{}
+				}
+				nils[n] = true
+			}
+		case v.Kind() == reflect.Pointer && v.IsNil():
+			if nils == nil {
+				nils = map[string]bool{}
+			}
+			nils[gocore.SnakeCase(f.Name)] = true
+		}
+	}
+	return nils
+}
+
+// prometheusMetric encodes a metric as a Prometheus metric.
+func prometheusMetric(id string, name, tag string, val reflect.Value, labels map[string]string) prometheus.Metric {
 	var metric float64
 	var property string
 
@@ -164,31 +264,43 @@ func prometheusMetric(id string, name, tag string, val reflect.Value) prometheus
 		valueType = prometheus.UntypedValue
 	}
 
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+	keys := slices.Sorted(maps.Keys(labels))
+	values := []string{id}
+	for _, k := range keys {
+		values = append(values, labels[k])
+	}
+
 	if t == "property" {
 		desc, ok := descs[name]
 		if !ok {
 			l := strings.SplitN(name, "_", 3) // pull out source
-			desc = prometheus.NewDesc(name, "property", []string{"id", "value"},
+			desc = prometheus.NewDesc(name, "property", append([]string{"id", "value"}, keys...),
 				prometheus.Labels{
 					"source": l[1],
 				})
 			descs[name] = desc
 		}
 
-		return prometheus.MustNewConstMetric(desc, valueType, 0.0, id, property)
+		return prometheus.MustNewConstMetric(desc, valueType, 0.0, append([]string{id, property}, values[1:]...)...)
 	}
 
 	desc, ok := descs[name]
 	if !ok {
 		l := strings.SplitN(name, "_", 3) // pull out source
-		desc = prometheus.NewDesc(name, "units: "+u, []string{"id"},
+		desc = prometheus.NewDesc(name, "units: "+u, append([]string{"id"}, keys...),
 			prometheus.Labels{
 				"source": l[1],
 			})
 		descs[name] = desc
 	}
 
-	return prometheus.MustNewConstMetric(desc, valueType, metric, id)
+	return prometheus.MustNewConstMetric(desc, valueType, metric, values...)
 }
 
 // scrapeInterval asks Prometheus for the scrape interval it will query gomon for metrics.
@@ -0,0 +1,54 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package serve
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/system"
+)
+
+// metricNames collects the names of the Prometheus metrics of a message.
+func metricNames(m message.Content) map[string]int {
+	ch := make(chan prometheus.Metric, 1000)
+	prometheusMetrics(ch, m)
+	close(ch)
+	names := map[string]int{}
+	for metric := range ch {
+		d := metric.Desc().String() // Desc{fqName: "name", ...
+		name, _, _ := strings.Cut(strings.TrimPrefix(d, `Desc{fqName: "`), `"`)
+		names[name]++
+	}
+	return names
+}
+
+func TestPrometheusMetricsAbsent(t *testing.T) {
+	m := &system.Measurement{Header: message.Measurement()}
+	m.Cpus = []system.Cpu{{Id: "cpu0"}, {Id: "cpu1"}}
+
+	// the first measurement, without utilization
+	names := metricNames(m)
+	if names["gomon_system_cpu_total_seconds"] != 1 || names["gomon_system_cpus_total_seconds"] != 2 {
+		t.Errorf("prometheusMetrics() omitted metrics of present structures: %v", names)
+	}
+	for name := range names {
+		if strings.Contains(name, "utilization") {
+			t.Errorf("prometheusMetrics() reported absent utilization %s", name)
+		}
+	}
+
+	// a later measurement, with utilization of the system and of one processor
+	m.Cpu.Utilization = &system.Utilization{Busy: 25}
+	m.Cpus[1].Utilization = &system.Utilization{Busy: 50}
+	names = metricNames(m)
+	if names["gomon_system_cpu_utilization_busy"] != 1 || names["gomon_system_cpus_utilization_busy"] != 1 {
+		t.Errorf("prometheusMetrics() utilization = %d system, %d processors, want 1 and 1",
+			names["gomon_system_cpu_utilization_busy"], names["gomon_system_cpus_utilization_busy"])
+	}
+	if names["gomon_system_cpus_total_seconds"] != 2 || names["gomon_system_cpu_frequency"] != 1 {
+		t.Errorf("prometheusMetrics() skipped metrics following an absent structure: %v", names)
+	}
+}
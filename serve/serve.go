@@ -5,6 +5,7 @@ package serve
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"strconv"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/process"
 	"golang.org/x/net/websocket"
 
 	// enable web server to handle /debug/pprof queries
@@ -54,6 +56,37 @@ func gomonHandler() error {
 	return nil
 }
 
+// treeHandler retrieves the process hierarchy, optionally rooted at a pid and with resource roll-ups.
+func treeHandler() error {
+	http.HandleFunc(
+		"/api/v1/tree",
+		func(w http.ResponseWriter, r *http.Request) {
+			measures.HttpRequests++
+			query := r.URL.Query()
+			var pid int
+			if v := query.Get("pid"); v != "" {
+				var err error
+				if pid, err = strconv.Atoi(v); err != nil {
+					http.Error(w, "invalid pid "+v, http.StatusBadRequest)
+					return
+				}
+			}
+			rollup, _ := strconv.ParseBool(query.Get("rollup"))
+			tree := process.Hierarchy(process.Pid(pid), rollup)
+			if pid > 0 && tree == nil {
+				http.Error(w, "pid "+strconv.Itoa(pid)+" not found", http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(tree); err != nil {
+				gocore.Error("tree Encode", err).Warn()
+			}
+		},
+	)
+	measures.Endpoints = append(measures.Endpoints, "api/v1/tree")
+	return nil
+}
+
 // wsHandler opens a web socket for delivering periodically an updated process NodeGraph.
 func wsHandler() error {
 	wsscheme := "ws"
@@ -131,6 +164,9 @@ func Serve(ctx context.Context) {
 	if err := gomonHandler(); err != nil {
 		gocore.Error("gomonHandler", err).Warn()
 	}
+	if err := treeHandler(); err != nil {
+		gocore.Error("treeHandler", err).Warn()
+	}
 	if err := wsHandler(); err != nil {
 		gocore.Error("wsHandler", err).Warn()
 	}
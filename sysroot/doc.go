@@ -0,0 +1,14 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package sysroot locates the procfs and sysfs filesystems from which the "gomon" command's Linux
+measurements and observations read. By default these are the filesystems mounted at /proc and /sys.
+When gomon runs in a container with the host's filesystems mounted elsewhere, such as /host/proc,
+the flags redirect every reader to them. Recorded snapshots of these filesystems may also be
+substituted to replay the parsers against known content.
+
+The sysroot package defines the following command line flags:
+* -procfs: the path at which procfs is mounted
+* -sysfs: the path at which sysfs is mounted
+*/
+package sysroot
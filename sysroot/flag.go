@@ -0,0 +1,34 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sysroot
+
+import (
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		procfs string
+		sysfs  string
+	}{
+		procfs: "/proc",
+		sysfs:  "/sys",
+	}
+)
+
+// init initializes the command line flags.
+func init() {
+	gocore.Flags.Var(
+		&flags.procfs,
+		"procfs",
+		"[-procfs <path>]",
+		"The `path` at which procfs is mounted, such as /host/proc in a container (linux only)",
+	)
+	gocore.Flags.Var(
+		&flags.sysfs,
+		"sysfs",
+		"[-sysfs <path>]",
+		"The `path` at which sysfs is mounted, such as /host/sys in a container (linux only)",
+	)
+}
@@ -0,0 +1,32 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sysroot
+
+import (
+	"path/filepath"
+)
+
+// Proc joins path elements to the root of procfs.
+func Proc(elem ...string) string {
+	return filepath.Join(append([]string{flags.procfs}, elem...)...)
+}
+
+// Sys joins path elements to the root of sysfs.
+func Sys(elem ...string) string {
+	return filepath.Join(append([]string{flags.sysfs}, elem...)...)
+}
+
+// Root joins path elements to the root of the host's filesystem, which when procfs is relocated
+// is reached through the root of the host's init process.
+func Root(elem ...string) string {
+	if Relocated() {
+		return Proc(append([]string{"1", "root"}, elem...)...)
+	}
+	return filepath.Join(append([]string{"/"}, elem...)...)
+}
+
+// Relocated reports whether procfs is read from other than /proc, in which case its content
+// describes a different host or pid namespace than that of gomon itself.
+func Relocated() bool {
+	return filepath.Clean(flags.procfs) != "/proc"
+}
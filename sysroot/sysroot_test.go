@@ -0,0 +1,47 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package sysroot
+
+import (
+	"testing"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestPaths(t *testing.T) {
+	defer func(procfs, sysfs string) {
+		flags.procfs, flags.sysfs = procfs, sysfs
+	}(flags.procfs, flags.sysfs)
+
+	tests := []struct {
+		procfs, sysfs string
+		relocated     bool
+		proc, sys     string
+		root          string
+	}{
+		{"/proc", "/sys", false, "/proc/1/stat", "/sys/class/hwmon", "/etc/os-release"},
+		{"/proc/", "/sys", false, "/proc/1/stat", "/sys/class/hwmon", "/etc/os-release"},
+		{"/host/proc", "/host/sys", true, "/host/proc/1/stat", "/host/sys/class/hwmon", "/host/proc/1/root/etc/os-release"},
+	}
+
+	for _, tt := range tests {
+		if err := gocore.Flags.FlagSet.Set("procfs", tt.procfs); err != nil {
+			t.Fatal(err)
+		}
+		if err := gocore.Flags.FlagSet.Set("sysfs", tt.sysfs); err != nil {
+			t.Fatal(err)
+		}
+		if got := Relocated(); got != tt.relocated {
+			t.Errorf("-procfs %s: Relocated() = %t, want %t", tt.procfs, got, tt.relocated)
+		}
+		if got := Proc("1", "stat"); got != tt.proc {
+			t.Errorf("-procfs %s: Proc() = %s, want %s", tt.procfs, got, tt.proc)
+		}
+		if got := Sys("class", "hwmon"); got != tt.sys {
+			t.Errorf("-sysfs %s: Sys() = %s, want %s", tt.sysfs, got, tt.sys)
+		}
+		if got := Root("etc", "os-release"); got != tt.root {
+			t.Errorf("-procfs %s: Root() = %s, want %s", tt.procfs, got, tt.root)
+		}
+	}
+}
@@ -14,6 +14,11 @@ import (
 func Measure(ps process.ProcStats) message.Content {
 	header := message.Measurement()
 	mem, swap := memory()
+	mem.UsedPercent = percent(float64(mem.Used), float64(mem.Total))
+	mem.UsedActualPercent = percent(float64(mem.UsedActual), float64(mem.Total))
+	swap.UsedPercent = percent(float64(swap.Used), float64(swap.Total))
+	c, cs := cpu(), cpus()
+	utilizations(&c, cs)
 	return &Measurement{
 		Header: header,
 		EventID: EventID{
@@ -27,11 +32,13 @@ func Measure(ps process.ProcStats) message.Content {
 			Rlimits:         rlimits(),
 			LoadAverage:     loadAverage(),
 			ContextSwitches: contextSwitches(),
-			Cpu:             cpu(),
+			Cpu:             c,
 			CpuCount:        runtime.NumCPU(),
-			Cpus:            cpus(),
+			Cpus:            cs,
 			Memory:          mem,
 			Swap:            swap,
+			Vm:              vm(),
+			Tables:          tables(),
 			ProcessStats:    ProcStats(ps),
 		},
 	}
@@ -321,3 +321,13 @@ func memory() (Memory, Swap) {
 			Used:  int(usage.xsu_used),
 		}
 }
+
+// vm captures the system's virtual memory statistics. Not reported on this platform.
+func vm() Vm {
+	return Vm{}
+}
+
+// tables captures the utilization of the kernel tables. Not reported on this platform.
+func tables() Tables {
+	return Tables{}
+}
@@ -5,12 +5,14 @@ package system
 import (
 	"bufio"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
 )
 
 var (
@@ -39,7 +41,7 @@ var (
 
 // loadAverage gets the system load averages.
 func loadAverage() LoadAverage {
-	buf, err := os.ReadFile("/proc/loadavg")
+	buf, err := os.ReadFile(sysroot.Proc("loadavg"))
 	if err != nil {
 		gocore.Error("/proc/loadavg", err).Err()
 		return LoadAverage{}
@@ -57,7 +59,7 @@ func loadAverage() LoadAverage {
 
 // contextSwitches queries count of system context switches.
 func contextSwitches() int {
-	f, err := os.Open("/proc/stat")
+	f, err := os.Open(sysroot.Proc("stat"))
 	if err != nil {
 		return 0
 	}
@@ -92,19 +94,17 @@ func rlimits() Rlimits {
 	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err == nil {
 		l.OpenFilesPerProcess = int(limit.Max)
 	}
-	if buf, err := os.ReadFile("/proc/sys/fs/file-max"); err == nil {
-		l.OpenFilesMaximum, _ = strconv.Atoi(string(buf))
-	}
+	l.OpenFilesMaximum = readInt(sysroot.Proc("sys", "fs", "file-max"))
 
 	return l
 }
 
 // cpu captures CPU metrics for system.
-func cpu() CPU {
-	f, err := os.Open("/proc/stat")
+func cpu() Cpu {
+	f, err := os.Open(sysroot.Proc("stat"))
 	if err != nil {
 		gocore.Error("/proc/stat open", err).Err()
-		return CPU{}
+		return Cpu{}
 	}
 	defer f.Close()
 
@@ -119,25 +119,29 @@ func cpu() CPU {
 	}
 
 	gocore.Error("/proc/stat cpu", sc.Err()).Err()
-	return CPU{}
+	return Cpu{}
 }
 
 // cpus captures individual CPU metrics.
-func cpus() []CPU {
-	f, err := os.Open("/proc/stat")
+func cpus() []Cpu {
+	f, err := os.Open(sysroot.Proc("stat"))
 	if err != nil {
 		gocore.Error("/proc/stat open", err).Err()
 		return nil
 	}
 	defer f.Close()
 
-	var cpus []CPU
+	var cpus []Cpu
 	sc := bufio.NewScanner(f)
 	for sc.Scan() {
 		l := sc.Text()
 		if len(l) > 3 && l[:3] == "cpu" && l[3] != ' ' {
-			_, v, _ := strings.Cut(l[3:], " ")
-			cpus = append(cpus, scale(v))
+			n, v, _ := strings.Cut(l[3:], " ")
+			c := scale(v)
+			c.Id = "cpu" + n
+			c.Topology = topology(c.Id)
+			c.Clock = clock(c.Id)
+			cpus = append(cpus, c)
 		}
 	}
 	if len(cpus) == 0 && sc.Err() != nil {
@@ -147,19 +151,59 @@ func cpus() []CPU {
 	return cpus
 }
 
-// scale converts cpu times to nanoseconds.
-func scale(stat string) CPU {
+// topology locates a processor by its core, package, and NUMA node.
+func topology(id string) Topology {
+	dirname := sysroot.Sys("devices", "system", "cpu", id)
+	t := Topology{
+		Core:    readString(filepath.Join(dirname, "topology", "core_id")),
+		Package: readString(filepath.Join(dirname, "topology", "physical_package_id")),
+	}
+	if nodes, _ := filepath.Glob(filepath.Join(dirname, "node[0-9]*")); len(nodes) > 0 {
+		t.Node = strings.TrimPrefix(filepath.Base(nodes[0]), "node")
+	}
+	return t
+}
+
+// clock reports a processor's frequency range and its thermal throttling.
+func clock(id string) Clock {
+	dirname := sysroot.Sys("devices", "system", "cpu", id)
+	return Clock{
+		Frequency:            readInt(filepath.Join(dirname, "cpufreq", "scaling_cur_freq")) * 1000, // kHz
+		MinFrequency:         readInt(filepath.Join(dirname, "cpufreq", "cpuinfo_min_freq")) * 1000,
+		MaxFrequency:         readInt(filepath.Join(dirname, "cpufreq", "cpuinfo_max_freq")) * 1000,
+		CoreThrottleCount:    readInt(filepath.Join(dirname, "thermal_throttle", "core_throttle_count")),
+		PackageThrottleCount: readInt(filepath.Join(dirname, "thermal_throttle", "package_throttle_count")),
+	}
+}
+
+// readString reads a single value file, such as those of sysfs.
+func readString(filename string) string {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// readInt reads a single integer value file, such as those of sysfs.
+func readInt(filename string) int {
+	n, _ := strconv.Atoi(readString(filename))
+	return n
+}
+
+// scale converts the cpu times of a /proc/stat cpu line, less its label, to nanoseconds.
+func scale(stat string) Cpu {
 	flds := strings.Fields(stat)
-	user, _ := strconv.Atoi(flds[1])
-	nice, _ := strconv.Atoi(flds[2])
-	system, _ := strconv.Atoi(flds[3])
-	idle, _ := strconv.Atoi(flds[4])
-	iowait, _ := strconv.Atoi(flds[5])
-	irq, _ := strconv.Atoi(flds[6])
-	softIrq, _ := strconv.Atoi(flds[7])
-	stolen, _ := strconv.Atoi(flds[8])
-
-	c := CPU{
+	user, _ := strconv.Atoi(flds[0])
+	nice, _ := strconv.Atoi(flds[1])
+	system, _ := strconv.Atoi(flds[2])
+	idle, _ := strconv.Atoi(flds[3])
+	iowait, _ := strconv.Atoi(flds[4])
+	irq, _ := strconv.Atoi(flds[5])
+	softIrq, _ := strconv.Atoi(flds[6])
+	stolen, _ := strconv.Atoi(flds[7])
+
+	c := Cpu{
 		User:    time.Duration(user) * factor,
 		System:  time.Duration(system) * factor,
 		Idle:    time.Duration(idle) * factor,
@@ -175,7 +219,7 @@ func scale(stat string) CPU {
 
 // memory captures system's memory and swap metrics.
 func memory() (Memory, Swap) {
-	i, err := gocore.Measures("/proc/meminfo")
+	i, err := gocore.Measures(sysroot.Proc("meminfo"))
 	if err != nil {
 		gocore.Error("/proc/meminfo", err).Err()
 	}
@@ -204,3 +248,176 @@ func memory() (Memory, Swap) {
 			Used:  swapTotal - swapFree,
 		}
 }
+
+// vm captures the system's virtual memory statistics.
+func vm() Vm {
+	s := map[string]int{}
+	f, err := os.Open(sysroot.Proc("vmstat"))
+	if err != nil {
+		gocore.Error("/proc/vmstat", err).Err()
+		return Vm{}
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), " "); ok {
+			s[k], _ = strconv.Atoi(v)
+		}
+	}
+
+	// direct reclaim and scan counts are reported per memory zone
+	var stalls, scans int
+	for k, v := range s {
+		if strings.HasPrefix(k, "allocstall") {
+			stalls += v
+		} else if strings.HasPrefix(k, "pgscan_direct") && k != "pgscan_direct_throttle" {
+			scans += v
+		}
+	}
+
+	i, err := gocore.Measures(sysroot.Proc("meminfo"))
+	if err != nil {
+		gocore.Error("/proc/meminfo", err).Err()
+	}
+	kb := func(k string) int { // meminfo reports kB
+		v, _ := strconv.Atoi(i[k])
+		return v * 1024
+	}
+	count := func(k string) int {
+		v, _ := strconv.Atoi(i[k])
+		return v
+	}
+
+	return Vm{
+		PagedIn:           s["pgpgin"] * 1024, // vmstat reports kB
+		PagedOut:          s["pgpgout"] * 1024,
+		SwappedIn:         s["pswpin"],
+		SwappedOut:        s["pswpout"],
+		PageFaults:        s["pgfault"],
+		MajorFaults:       s["pgmajfault"],
+		OomKills:          s["oom_kill"],
+		DirectReclaims:    stalls,
+		DirectScans:       scans,
+		CompactionStalls:  s["compact_stall"],
+		CompactionFails:   s["compact_fail"],
+		Active:            kb("Active"),
+		Inactive:          kb("Inactive"),
+		ActiveAnon:        kb("Active(anon)"),
+		InactiveAnon:      kb("Inactive(anon)"),
+		ActiveFile:        kb("Active(file)"),
+		InactiveFile:      kb("Inactive(file)"),
+		Dirty:             kb("Dirty"),
+		Writeback:         kb("Writeback"),
+		AnonPages:         kb("AnonPages"),
+		Mapped:            kb("Mapped"),
+		Shmem:             kb("Shmem"),
+		Slab:              kb("Slab"),
+		SlabReclaimable:   kb("SReclaimable"),
+		SlabUnreclaimable: kb("SUnreclaim"),
+		PageTables:        kb("PageTables"),
+		CommitLimit:       kb("CommitLimit"),
+		CommittedAs:       kb("Committed_AS"),
+		AnonHugePages:     kb("AnonHugePages"),
+		HugePagesTotal:    count("HugePages_Total"),
+		HugePagesFree:     count("HugePages_Free"),
+		HugePagesReserved: count("HugePages_Rsvd"),
+		HugePagesSurplus:  count("HugePages_Surp"),
+		HugePageSize:      kb("Hugepagesize"),
+	}
+}
+
+// tables captures the utilization of the kernel's file, inode, socket, and connection tracking tables.
+func tables() Tables {
+	var t Tables
+
+	if f := readInts(sysroot.Proc("sys", "fs", "file-nr")); len(f) == 3 {
+		t.Files = Files{
+			Allocated:   f[0],
+			Unused:      f[1],
+			Maximum:     f[2],
+			UsedPercent: percent(float64(f[0]-f[1]), float64(f[2])),
+		}
+	}
+	if f := readInts(sysroot.Proc("sys", "fs", "inode-nr")); len(f) >= 2 {
+		t.Inodes = Inodes{
+			Allocated: f[0],
+			Free:      f[1],
+		}
+	}
+
+	s := sockstat()
+	pagesize := os.Getpagesize()
+	t.Sockets = Sockets{
+		Used:               s["sockets.used"],
+		TcpInUse:           s["TCP.inuse"],
+		TcpAllocated:       s["TCP.alloc"],
+		TcpOrphans:         s["TCP.orphan"],
+		TcpOrphansMaximum:  readInt(sysroot.Proc("sys", "net", "ipv4", "tcp_max_orphans")),
+		TcpTimeWait:        s["TCP.tw"],
+		TcpTimeWaitMaximum: readInt(sysroot.Proc("sys", "net", "ipv4", "tcp_max_tw_buckets")),
+		TcpMemory:          s["TCP.mem"] * pagesize,
+		Tcp6InUse:          s["TCP6.inuse"],
+		UdpInUse:           s["UDP.inuse"],
+		UdpMemory:          s["UDP.mem"] * pagesize,
+		Udp6InUse:          s["UDP6.inuse"],
+		RawInUse:           s["RAW.inuse"],
+		Raw6InUse:          s["RAW6.inuse"],
+		FragmentsInUse:     s["FRAG.inuse"] + s["FRAG6.inuse"],
+		FragmentsMemory:    s["FRAG.memory"] + s["FRAG6.memory"],
+	}
+	// the memory limits are the thresholds (min, pressure, max) in pages at which the kernel refuses allocations
+	if f := readInts(sysroot.Proc("sys", "net", "ipv4", "tcp_mem")); len(f) == 3 {
+		t.Sockets.TcpMemoryMaximum = f[2] * pagesize
+	}
+	if f := readInts(sysroot.Proc("sys", "net", "ipv4", "udp_mem")); len(f) == 3 {
+		t.Sockets.UdpMemoryMaximum = f[2] * pagesize
+	}
+	t.Sockets.TcpOrphansPercent = percent(float64(t.Sockets.TcpOrphans), float64(t.Sockets.TcpOrphansMaximum))
+	t.Sockets.TcpTimeWaitPercent = percent(float64(t.Sockets.TcpTimeWait), float64(t.Sockets.TcpTimeWaitMaximum))
+	t.Sockets.TcpMemoryPercent = percent(float64(t.Sockets.TcpMemory), float64(t.Sockets.TcpMemoryMaximum))
+	t.Sockets.UdpMemoryPercent = percent(float64(t.Sockets.UdpMemory), float64(t.Sockets.UdpMemoryMaximum))
+
+	// present only when the nf_conntrack module is loaded
+	t.Conntrack.Count = readInt(sysroot.Proc("sys", "net", "netfilter", "nf_conntrack_count"))
+	t.Conntrack.Maximum = readInt(sysroot.Proc("sys", "net", "netfilter", "nf_conntrack_max"))
+	t.Conntrack.UsedPercent = percent(float64(t.Conntrack.Count), float64(t.Conntrack.Maximum))
+
+	return t
+}
+
+// sockstat reads the IPv4 and IPv6 socket statistics into a map keyed by protocol and statistic, e.g. TCP.inuse.
+func sockstat() map[string]int {
+	s := map[string]int{}
+	for _, name := range []string{"sockstat", "sockstat6"} {
+		f, err := os.Open(sysroot.Proc("net", name))
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			proto, stats, ok := strings.Cut(sc.Text(), ":")
+			if !ok {
+				continue
+			}
+			fs := strings.Fields(stats)
+			for i := 0; i+1 < len(fs); i += 2 {
+				s[proto+"."+fs[i]], _ = strconv.Atoi(fs[i+1])
+			}
+		}
+		f.Close()
+	}
+	return s
+}
+
+// readInts reads a file of whitespace separated integers, such as those of procfs.
+func readInts(filename string) []int {
+	var ns []int
+	for _, f := range strings.Fields(readString(filename)) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		ns = append(ns, n)
+	}
+	return ns
+}
@@ -0,0 +1,195 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package system
+
+import (
+	"maps"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/zosmac/gocore"
+)
+
+func TestMain(m *testing.M) {
+	gocore.Flags.FlagSet.Set("procfs", "testdata/proc")
+	gocore.Flags.FlagSet.Set("sysfs", "testdata/sys")
+	os.Exit(m.Run())
+}
+
+func TestLoadAverage(t *testing.T) {
+	want := LoadAverage{OneMinute: 0.52, FiveMinute: 0.58, FifteenMinute: 0.59}
+	if got := loadAverage(); got != want {
+		t.Errorf("loadAverage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestContextSwitches(t *testing.T) {
+	if got := contextSwitches(); got != 987654 {
+		t.Errorf("contextSwitches() = %d, want 987654", got)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	mem, swap := memory()
+	if mem.Total != 8000000 || mem.Free != 2000000 || mem.Used != 6000000 ||
+		mem.FreeActual != 5000000 || mem.UsedActual != 3000000 {
+		t.Errorf("memory() = %+v", mem)
+	}
+	if swap.Total != 1000000 || swap.Free != 750000 || swap.Used != 250000 {
+		t.Errorf("memory() swap = %+v", swap)
+	}
+}
+
+func TestCpu(t *testing.T) {
+	c := cpu()
+	if c.User != 1000*factor || c.Idle != 8000*factor || c.Stolen != 5*factor {
+		t.Errorf("cpu() = %+v", c)
+	}
+	if want := time.Duration(1000+20+300+8000+50+0+10+5) * factor; c.Total != want {
+		t.Errorf("cpu() total = %v, want %v", c.Total, want)
+	}
+
+	cs := cpus()
+	if len(cs) != 2 || cs[0].Id != "cpu0" || cs[1].Id != "cpu1" {
+		t.Fatalf("cpus() = %+v", cs)
+	}
+	if cs[1].User != 400*factor {
+		t.Errorf("cpus() cpu1 user = %v, want %v", cs[1].User, 400*factor)
+	}
+}
+
+func TestTopologyClock(t *testing.T) {
+	want := []struct {
+		Topology
+		Clock
+	}{
+		{
+			Topology{Core: "0", Package: "0", Node: "0"},
+			Clock{Frequency: 2400000000, MinFrequency: 800000000, MaxFrequency: 3600000000, CoreThrottleCount: 2, PackageThrottleCount: 7},
+		},
+		{
+			Topology{Core: "4", Package: "1", Node: "1"},
+			Clock{Frequency: 2500000000, MinFrequency: 800000000, MaxFrequency: 3600000000, CoreThrottleCount: 3, PackageThrottleCount: 7},
+		},
+	}
+
+	cs := cpus()
+	if len(cs) != len(want) {
+		t.Fatalf("cpus() = %+v", cs)
+	}
+	for i, c := range cs {
+		if c.Topology != want[i].Topology {
+			t.Errorf("%s topology = %+v, want %+v", c.Id, c.Topology, want[i].Topology)
+		}
+		if c.Clock != want[i].Clock {
+			t.Errorf("%s clock = %+v, want %+v", c.Id, c.Clock, want[i].Clock)
+		}
+	}
+
+	if got, want := cs[1].Labels(), map[string]string{"cpu": "cpu1", "package": "1", "node": "1"}; !maps.Equal(got, want) {
+		t.Errorf("Labels() = %v, want %v", got, want)
+	}
+}
+
+func TestVm(t *testing.T) {
+	want := Vm{
+		PagedIn:           742106 * 1024,
+		PagedOut:          2613356 * 1024,
+		SwappedIn:         12,
+		SwappedOut:        34,
+		PageFaults:        33750675,
+		MajorFaults:       1037,
+		OomKills:          1,
+		DirectReclaims:    3 + 40 + 2,
+		DirectScans:       700,
+		CompactionStalls:  6,
+		CompactionFails:   4,
+		Active:            1200108 * 1024,
+		Inactive:          1104736 * 1024,
+		ActiveAnon:        28 * 1024,
+		InactiveAnon:      188216 * 1024,
+		ActiveFile:        1200080 * 1024,
+		InactiveFile:      916520 * 1024,
+		Dirty:             18788 * 1024,
+		Writeback:         12 * 1024,
+		AnonPages:         188720 * 1024,
+		Mapped:            153816 * 1024,
+		Shmem:             9484 * 1024,
+		Slab:              118696 * 1024,
+		SlabReclaimable:   94456 * 1024,
+		SlabUnreclaimable: 24240 * 1024,
+		PageTables:        2600 * 1024,
+		CommitLimit:       3073700 * 1024,
+		CommittedAs:       582796 * 1024,
+		AnonHugePages:     4096 * 1024,
+		HugePagesTotal:    16,
+		HugePagesFree:     10,
+		HugePagesReserved: 2,
+		HugePagesSurplus:  1,
+		HugePageSize:      2048 * 1024,
+	}
+	if got := vm(); got != want {
+		t.Errorf("vm() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSockstat(t *testing.T) {
+	want := map[string]int{
+		"sockets.used":   240,
+		"TCP.inuse":      60,
+		"TCP.orphan":     8,
+		"TCP.tw":         120,
+		"TCP.alloc":      64,
+		"TCP.mem":        225,
+		"UDP.inuse":      5,
+		"UDP.mem":        12,
+		"UDPLITE.inuse":  0,
+		"RAW.inuse":      1,
+		"FRAG.inuse":     2,
+		"FRAG.memory":    4096,
+		"TCP6.inuse":     7,
+		"UDP6.inuse":     3,
+		"UDPLITE6.inuse": 0,
+		"RAW6.inuse":     1,
+		"FRAG6.inuse":    1,
+		"FRAG6.memory":   2048,
+	}
+	if got := sockstat(); !maps.Equal(got, want) {
+		t.Errorf("sockstat() = %v, want %v", got, want)
+	}
+}
+
+func TestTables(t *testing.T) {
+	tb := tables()
+
+	if want := (Files{Allocated: 3020, Unused: 20, Maximum: 612720, UsedPercent: 0.49}); tb.Files != want {
+		t.Errorf("tables() files = %+v, want %+v", tb.Files, want)
+	}
+	if want := (Inodes{Allocated: 32221, Free: 1200}); tb.Inodes != want {
+		t.Errorf("tables() inodes = %+v, want %+v", tb.Inodes, want)
+	}
+	if want := (Conntrack{Count: 1000, Maximum: 262144, UsedPercent: 0.38}); tb.Conntrack != want {
+		t.Errorf("tables() conntrack = %+v, want %+v", tb.Conntrack, want)
+	}
+
+	pagesize := os.Getpagesize()
+	s := tb.Sockets
+	if s.Used != 240 || s.TcpInUse != 60 || s.TcpAllocated != 64 || s.Tcp6InUse != 7 ||
+		s.UdpInUse != 5 || s.Udp6InUse != 3 || s.RawInUse != 1 || s.Raw6InUse != 1 ||
+		s.FragmentsInUse != 3 || s.FragmentsMemory != 6144 {
+		t.Errorf("tables() sockets = %+v", s)
+	}
+	if s.TcpOrphans != 8 || s.TcpOrphansMaximum != 32768 || s.TcpOrphansPercent != 0.02 {
+		t.Errorf("tables() tcp orphans = %d of %d (%g%%)", s.TcpOrphans, s.TcpOrphansMaximum, s.TcpOrphansPercent)
+	}
+	if s.TcpTimeWait != 120 || s.TcpTimeWaitMaximum != 32768 || s.TcpTimeWaitPercent != 0.37 {
+		t.Errorf("tables() tcp time wait = %d of %d (%g%%)", s.TcpTimeWait, s.TcpTimeWaitMaximum, s.TcpTimeWaitPercent)
+	}
+	if s.TcpMemory != 225*pagesize || s.TcpMemoryMaximum != 141360*pagesize || s.TcpMemoryPercent != 0.16 {
+		t.Errorf("tables() tcp memory = %d of %d (%g%%)", s.TcpMemory, s.TcpMemoryMaximum, s.TcpMemoryPercent)
+	}
+	if s.UdpMemory != 12*pagesize || s.UdpMemoryMaximum != 282726*pagesize || s.UdpMemoryPercent != 0 {
+		t.Errorf("tables() udp memory = %d of %d (%g%%)", s.UdpMemory, s.UdpMemoryMaximum, s.UdpMemoryPercent)
+	}
+}
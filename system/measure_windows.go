@@ -128,7 +128,7 @@ func rlimits() Rlimits {
 }
 
 // cpu captures CPU metrics for system.
-func cpu() CPU {
+func cpu() Cpu {
 	var lpIdleTime, lpKernelTime, lpUserTime windows.Filetime
 	_, _, err := getSystemTimes(
 		uintptr(unsafe.Pointer(&lpIdleTime)),
@@ -137,10 +137,10 @@ func cpu() CPU {
 	)
 	if err != nil {
 		gocore.Error("GetSystemTimes", err).Err()
-		return CPU{}
+		return Cpu{}
 	}
 
-	c := CPU{
+	c := Cpu{
 		User:   time.Duration(lpUserTime.Nanoseconds()),
 		System: time.Duration(lpKernelTime.Nanoseconds()),
 		Idle:   time.Duration(lpIdleTime.Nanoseconds()),
@@ -150,7 +150,7 @@ func cpu() CPU {
 }
 
 // cpus captures individual CPU metrics. Unsupported on windows.
-func cpus() []CPU {
+func cpus() []Cpu {
 	return nil
 }
 
@@ -182,3 +182,13 @@ func memory() (Memory, Swap) {
 			Used:  int(swapTotal - swapFree),
 		}
 }
+
+// vm captures the system's virtual memory statistics. Not reported on this platform.
+func vm() Vm {
+	return Vm{}
+}
+
+// tables captures the utilization of the kernel tables. Not reported on this platform.
+func tables() Tables {
+	return Tables{}
+}
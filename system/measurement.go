@@ -35,31 +35,160 @@ type (
 
 	// Cpu holds the Cpu metrics for the system and for an individual processor.
 	Cpu struct {
-		Total   time.Duration `json:"total" gomon:"counter,ns"`
-		User    time.Duration `json:"user" gomon:"counter,ns"`
-		System  time.Duration `json:"system" gomon:"counter,ns"`
-		Idle    time.Duration `json:"idle" gomon:"counter,ns"`
-		Nice    time.Duration `json:"nice,omitempty" gomon:"counter,ns,linux"`
-		IoWait  time.Duration `json:"io_wait,omitempty" gomon:"counter,ns,linux"`
-		Stolen  time.Duration `json:"stolen,omitempty" gomon:"counter,ns,linux"`
-		Irq     time.Duration `json:"irq,omitempty" gomon:"counter,ns,linux"`
-		SoftIrq time.Duration `json:"soft_irq,omitempty" gomon:"counter,ns,linux"`
+		Id          string        `json:"id,omitempty" gomon:"property"` // of an individual processor
+		Total       time.Duration `json:"total" gomon:"counter,ns"`
+		User        time.Duration `json:"user" gomon:"counter,ns"`
+		System      time.Duration `json:"system" gomon:"counter,ns"`
+		Idle        time.Duration `json:"idle" gomon:"counter,ns"`
+		Nice        time.Duration `json:"nice,omitempty" gomon:"counter,ns,linux"`
+		IoWait      time.Duration `json:"io_wait,omitempty" gomon:"counter,ns,linux"`
+		Stolen      time.Duration `json:"stolen,omitempty" gomon:"counter,ns,linux"`
+		Irq         time.Duration `json:"irq,omitempty" gomon:"counter,ns,linux"`
+		SoftIrq     time.Duration `json:"soft_irq,omitempty" gomon:"counter,ns,linux"`
+		Topology    `gomon:""`
+		Clock       `gomon:""`
+		Utilization *Utilization `json:"utilization,omitempty" gomon:""` // absent without a previous measurement
+	}
+
+	// Topology locates an individual processor.
+	Topology struct {
+		Core    string `json:"core,omitempty" gomon:"property,,linux"`
+		Package string `json:"package,omitempty" gomon:"property,,linux"`
+		Node    string `json:"node,omitempty" gomon:"property,,linux"` // NUMA
+	}
+
+	// Clock contains the clock frequency and thermal throttling of an individual processor.
+	Clock struct {
+		Frequency            int `json:"frequency,omitempty" gomon:"gauge,Hz,linux"`
+		MinFrequency         int `json:"min_frequency,omitempty" gomon:"gauge,Hz,linux"`
+		MaxFrequency         int `json:"max_frequency,omitempty" gomon:"gauge,Hz,linux"`
+		CoreThrottleCount    int `json:"core_throttle_count,omitempty" gomon:"counter,count,linux"`
+		PackageThrottleCount int `json:"package_throttle_count,omitempty" gomon:"counter,count,linux"`
+	}
+
+	// Utilization contains the percentages of processor time spent in each mode since the previous measurement.
+	Utilization struct {
+		Busy    float64 `json:"busy" gomon:"gauge,%"` // all but idle and I/O wait
+		User    float64 `json:"user" gomon:"gauge,%"`
+		System  float64 `json:"system" gomon:"gauge,%"`
+		Idle    float64 `json:"idle" gomon:"gauge,%"`
+		Nice    float64 `json:"nice,omitempty" gomon:"gauge,%,linux"`
+		IoWait  float64 `json:"io_wait,omitempty" gomon:"gauge,%,linux"`
+		Stolen  float64 `json:"stolen,omitempty" gomon:"gauge,%,linux"`
+		Irq     float64 `json:"irq,omitempty" gomon:"gauge,%,linux"`
+		SoftIrq float64 `json:"soft_irq,omitempty" gomon:"gauge,%,linux"`
 	}
 
 	// Memory contains the system's memory metrics.
 	Memory struct {
-		Total      int `json:"total" gomon:"gauge,B"`
-		Free       int `json:"free" gomon:"gauge,B"`
-		Used       int `json:"used" gomon:"gauge,B"`
-		FreeActual int `json:"free_actual" gomon:"gauge,B"`
-		UsedActual int `json:"used_actual" gomon:"gauge,B"`
+		Total             int     `json:"total" gomon:"gauge,B"`
+		Free              int     `json:"free" gomon:"gauge,B"`
+		Used              int     `json:"used" gomon:"gauge,B"`
+		FreeActual        int     `json:"free_actual" gomon:"gauge,B"`
+		UsedActual        int     `json:"used_actual" gomon:"gauge,B"`
+		UsedPercent       float64 `json:"used_percent" gomon:"gauge,%"`
+		UsedActualPercent float64 `json:"used_actual_percent" gomon:"gauge,%"`
 	}
 
 	// Swap contains the system's swap metrics.
 	Swap struct {
-		Total int `json:"total" gomon:"gauge,B"`
-		Free  int `json:"free" gomon:"gauge,B"`
-		Used  int `json:"used" gomon:"gauge,B"`
+		Total       int     `json:"total" gomon:"gauge,B"`
+		Free        int     `json:"free" gomon:"gauge,B"`
+		Used        int     `json:"used" gomon:"gauge,B"`
+		UsedPercent float64 `json:"used_percent" gomon:"gauge,%"`
+	}
+
+	// Vm contains the system's virtual memory statistics.
+	Vm struct {
+		PagedIn           int `json:"paged_in,omitempty" gomon:"counter,B,linux"`
+		PagedOut          int `json:"paged_out,omitempty" gomon:"counter,B,linux"`
+		SwappedIn         int `json:"swapped_in,omitempty" gomon:"counter,count,linux"`  // pages
+		SwappedOut        int `json:"swapped_out,omitempty" gomon:"counter,count,linux"` // pages
+		PageFaults        int `json:"page_faults,omitempty" gomon:"counter,count,linux"`
+		MajorFaults       int `json:"major_faults,omitempty" gomon:"counter,count,linux"`
+		OomKills          int `json:"oom_kills,omitempty" gomon:"counter,count,linux"`
+		DirectReclaims    int `json:"direct_reclaims,omitempty" gomon:"counter,count,linux"` // allocation stalls
+		DirectScans       int `json:"direct_scans,omitempty" gomon:"counter,count,linux"`    // pages
+		CompactionStalls  int `json:"compaction_stalls,omitempty" gomon:"counter,count,linux"`
+		CompactionFails   int `json:"compaction_fails,omitempty" gomon:"counter,count,linux"`
+		Active            int `json:"active,omitempty" gomon:"gauge,B,linux"`
+		Inactive          int `json:"inactive,omitempty" gomon:"gauge,B,linux"`
+		ActiveAnon        int `json:"active_anon,omitempty" gomon:"gauge,B,linux"`
+		InactiveAnon      int `json:"inactive_anon,omitempty" gomon:"gauge,B,linux"`
+		ActiveFile        int `json:"active_file,omitempty" gomon:"gauge,B,linux"`
+		InactiveFile      int `json:"inactive_file,omitempty" gomon:"gauge,B,linux"`
+		Dirty             int `json:"dirty,omitempty" gomon:"gauge,B,linux"`
+		Writeback         int `json:"writeback,omitempty" gomon:"gauge,B,linux"`
+		AnonPages         int `json:"anon_pages,omitempty" gomon:"gauge,B,linux"`
+		Mapped            int `json:"mapped,omitempty" gomon:"gauge,B,linux"`
+		Shmem             int `json:"shmem,omitempty" gomon:"gauge,B,linux"`
+		Slab              int `json:"slab,omitempty" gomon:"gauge,B,linux"`
+		SlabReclaimable   int `json:"slab_reclaimable,omitempty" gomon:"gauge,B,linux"`
+		SlabUnreclaimable int `json:"slab_unreclaimable,omitempty" gomon:"gauge,B,linux"`
+		PageTables        int `json:"page_tables,omitempty" gomon:"gauge,B,linux"`
+		CommitLimit       int `json:"commit_limit,omitempty" gomon:"gauge,B,linux"`
+		CommittedAs       int `json:"committed_as,omitempty" gomon:"gauge,B,linux"`
+		AnonHugePages     int `json:"anon_huge_pages,omitempty" gomon:"gauge,B,linux"`
+		HugePagesTotal    int `json:"huge_pages_total,omitempty" gomon:"gauge,count,linux"`
+		HugePagesFree     int `json:"huge_pages_free,omitempty" gomon:"gauge,count,linux"`
+		HugePagesReserved int `json:"huge_pages_reserved,omitempty" gomon:"gauge,count,linux"`
+		HugePagesSurplus  int `json:"huge_pages_surplus,omitempty" gomon:"gauge,count,linux"`
+		HugePageSize      int `json:"huge_page_size,omitempty" gomon:"gauge,B,linux"`
+	}
+
+	// Files contains the utilization of the kernel's file handle table.
+	Files struct {
+		Allocated   int     `json:"allocated,omitempty" gomon:"gauge,count,linux"`
+		Unused      int     `json:"unused,omitempty" gomon:"gauge,count,linux"` // allocated but not in use
+		Maximum     int     `json:"maximum,omitempty" gomon:"gauge,count,linux"`
+		UsedPercent float64 `json:"used_percent,omitempty" gomon:"gauge,%,linux"`
+	}
+
+	// Inodes contains the utilization of the kernel's inode cache.
+	Inodes struct {
+		Allocated int `json:"allocated,omitempty" gomon:"gauge,count,linux"`
+		Free      int `json:"free,omitempty" gomon:"gauge,count,linux"`
+	}
+
+	// Sockets contains the utilization of the kernel's socket tables.
+	Sockets struct {
+		Used               int     `json:"used,omitempty" gomon:"gauge,count,linux"`
+		TcpInUse           int     `json:"tcp_in_use,omitempty" gomon:"gauge,count,linux"`
+		TcpAllocated       int     `json:"tcp_allocated,omitempty" gomon:"gauge,count,linux"`
+		TcpOrphans         int     `json:"tcp_orphans,omitempty" gomon:"gauge,count,linux"`
+		TcpOrphansMaximum  int     `json:"tcp_orphans_maximum,omitempty" gomon:"gauge,count,linux"`
+		TcpOrphansPercent  float64 `json:"tcp_orphans_percent,omitempty" gomon:"gauge,%,linux"`
+		TcpTimeWait        int     `json:"tcp_time_wait,omitempty" gomon:"gauge,count,linux"`
+		TcpTimeWaitMaximum int     `json:"tcp_time_wait_maximum,omitempty" gomon:"gauge,count,linux"`
+		TcpTimeWaitPercent float64 `json:"tcp_time_wait_percent,omitempty" gomon:"gauge,%,linux"`
+		TcpMemory          int     `json:"tcp_memory,omitempty" gomon:"gauge,B,linux"`
+		TcpMemoryMaximum   int     `json:"tcp_memory_maximum,omitempty" gomon:"gauge,B,linux"`
+		TcpMemoryPercent   float64 `json:"tcp_memory_percent,omitempty" gomon:"gauge,%,linux"`
+		Tcp6InUse          int     `json:"tcp6_in_use,omitempty" gomon:"gauge,count,linux"`
+		UdpInUse           int     `json:"udp_in_use,omitempty" gomon:"gauge,count,linux"`
+		UdpMemory          int     `json:"udp_memory,omitempty" gomon:"gauge,B,linux"`
+		UdpMemoryMaximum   int     `json:"udp_memory_maximum,omitempty" gomon:"gauge,B,linux"`
+		UdpMemoryPercent   float64 `json:"udp_memory_percent,omitempty" gomon:"gauge,%,linux"`
+		Udp6InUse          int     `json:"udp6_in_use,omitempty" gomon:"gauge,count,linux"`
+		RawInUse           int     `json:"raw_in_use,omitempty" gomon:"gauge,count,linux"`
+		Raw6InUse          int     `json:"raw6_in_use,omitempty" gomon:"gauge,count,linux"`
+		FragmentsInUse     int     `json:"fragments_in_use,omitempty" gomon:"gauge,count,linux"`
+		FragmentsMemory    int     `json:"fragments_memory,omitempty" gomon:"gauge,B,linux"`
+	}
+
+	// Conntrack contains the utilization of the netfilter connection tracking table.
+	Conntrack struct {
+		Count       int     `json:"count,omitempty" gomon:"gauge,count,linux"`
+		Maximum     int     `json:"maximum,omitempty" gomon:"gauge,count,linux"`
+		UsedPercent float64 `json:"used_percent,omitempty" gomon:"gauge,%,linux"`
+	}
+
+	// Tables contains the utilization of the kernel tables that limit the files, inodes, sockets, and connections.
+	Tables struct {
+		Files     Files     `json:"files" gomon:""`
+		Inodes    Inodes    `json:"inodes" gomon:""`
+		Sockets   Sockets   `json:"sockets" gomon:""`
+		Conntrack Conntrack `json:"conntrack" gomon:""`
 	}
 
 	// Metrics defines measurement metrics.
@@ -73,6 +202,8 @@ type (
 		Cpus            []Cpu       `json:"cpus" gomon:""`
 		Memory          Memory      `json:"memory" gomon:""`
 		Swap            Swap        `json:"swap" gomon:""`
+		Vm              Vm          `json:"vm" gomon:""`
+		Tables          Tables      `json:"tables" gomon:""`
 		ProcessStats    ProcStats   `json:"process_stats" gomon:""`
 	}
 
@@ -94,3 +225,12 @@ func (*Measurement) Events() []string {
 func (m *Measurement) ID() string {
 	return m.EventID.Name
 }
+
+// Labels returns the labels that distinguish the metrics of an individual processor.
+func (c Cpu) Labels() map[string]string {
+	return map[string]string{
+		"cpu":     c.Id,
+		"package": c.Package,
+		"node":    c.Node,
+	}
+}
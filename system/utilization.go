@@ -0,0 +1,82 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package system
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// prevCpus records the CPU times of the previous measurement, keyed by processor id, or "" for the system.
+	prevCpus     = map[string]Cpu{}
+	prevCpusLock sync.Mutex
+)
+
+// utilizations derives the utilization percentages of the system's CPU and of each processor from the previous measurement.
+func utilizations(cpu *Cpu, cpus []Cpu) {
+	prevCpusLock.Lock()
+	defer prevCpusLock.Unlock()
+
+	curr := make(map[string]Cpu, len(cpus)+1)
+	if prev, ok := prevCpus[""]; ok {
+		cpu.Utilization = utilization(prev, *cpu)
+	}
+	curr[""] = *cpu
+	for i, c := range cpus {
+		if prev, ok := prevCpus[c.Id]; ok {
+			cpus[i].Utilization = utilization(prev, c)
+		}
+		curr[c.Id] = c
+	}
+	prevCpus = curr
+}
+
+// utilization computes the percentages of CPU time spent in each mode between two measurements.
+// If any time decreased, as when a processor is taken offline and brought back or the system resumes
+// from suspend, the counters have reset and no utilization is reported until the next measurement.
+func utilization(prev, curr Cpu) *Utilization {
+	modes := [][2]time.Duration{
+		{prev.User, curr.User},
+		{prev.System, curr.System},
+		{prev.Idle, curr.Idle},
+		{prev.Nice, curr.Nice},
+		{prev.IoWait, curr.IoWait},
+		{prev.Stolen, curr.Stolen},
+		{prev.Irq, curr.Irq},
+		{prev.SoftIrq, curr.SoftIrq},
+	}
+	for _, m := range modes {
+		if m[1] < m[0] {
+			return nil
+		}
+	}
+	total := curr.Total - prev.Total
+	if total <= 0 {
+		return nil
+	}
+
+	pct := func(p, c time.Duration) float64 {
+		return percent(float64(c-p), float64(total))
+	}
+	return &Utilization{
+		Busy:    percent(float64(total-(curr.Idle-prev.Idle)-(curr.IoWait-prev.IoWait)), float64(total)),
+		User:    pct(prev.User, curr.User),
+		System:  pct(prev.System, curr.System),
+		Idle:    pct(prev.Idle, curr.Idle),
+		Nice:    pct(prev.Nice, curr.Nice),
+		IoWait:  pct(prev.IoWait, curr.IoWait),
+		Stolen:  pct(prev.Stolen, curr.Stolen),
+		Irq:     pct(prev.Irq, curr.Irq),
+		SoftIrq: pct(prev.SoftIrq, curr.SoftIrq),
+	}
+}
+
+// percent computes a part's percentage of a whole, rounded to hundredths.
+func percent(part, whole float64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return math.Round(10000*part/whole) / 100
+}
@@ -0,0 +1,85 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+// sample builds the CPU times of a measurement.
+func sample(id string, user, system, idle, iowait time.Duration) Cpu {
+	return Cpu{
+		Id:     id,
+		User:   user,
+		System: system,
+		Idle:   idle,
+		IoWait: iowait,
+		Total:  user + system + idle + iowait,
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	prev := sample("cpu0", 1000, 500, 8000, 500)
+
+	u := utilization(prev, sample("cpu0", 1300, 600, 8500, 600))
+	if want := (Utilization{Busy: 40, User: 30, System: 10, Idle: 50, IoWait: 10}); u == nil || *u != want {
+		t.Errorf("utilization() = %+v, want %+v", u, want)
+	}
+
+	// the counters reset, as when a processor is brought back online
+	if u := utilization(prev, sample("cpu0", 100, 50, 800, 50)); u != nil {
+		t.Errorf("utilization() after reset = %+v, want nil", u)
+	}
+
+	// a single mode decreased while the total increased
+	if u := utilization(prev, sample("cpu0", 900, 500, 9000, 500)); u != nil {
+		t.Errorf("utilization() after decrease = %+v, want nil", u)
+	}
+
+	// no time elapsed
+	if u := utilization(prev, prev); u != nil {
+		t.Errorf("utilization() unchanged = %+v, want nil", u)
+	}
+}
+
+func TestUtilizations(t *testing.T) {
+	prevCpusLock.Lock()
+	saved := prevCpus
+	prevCpus = map[string]Cpu{}
+	prevCpusLock.Unlock()
+	defer func() {
+		prevCpusLock.Lock()
+		prevCpus = saved
+		prevCpusLock.Unlock()
+	}()
+
+	cpu := sample("", 2000, 1000, 16000, 1000)
+	cpus := []Cpu{sample("cpu0", 1000, 500, 8000, 500), sample("cpu1", 1000, 500, 8000, 500)}
+	utilizations(&cpu, cpus)
+	if cpu.Utilization != nil || cpus[0].Utilization != nil || cpus[1].Utilization != nil {
+		t.Fatalf("utilizations() without previous measurement = %+v %+v", cpu, cpus)
+	}
+
+	// cpu1 went offline and came back, resetting its counters
+	cpu = sample("", 2300, 1100, 16500, 1100)
+	cpus = []Cpu{sample("cpu0", 1300, 600, 8500, 600), sample("cpu1", 10, 5, 80, 5)}
+	utilizations(&cpu, cpus)
+	if cpu.Utilization == nil || cpu.Utilization.User != 30 {
+		t.Errorf("utilizations() system = %+v", cpu.Utilization)
+	}
+	if cpus[0].Utilization == nil || cpus[0].Utilization.Busy != 40 {
+		t.Errorf("utilizations() cpu0 = %+v", cpus[0].Utilization)
+	}
+	if cpus[1].Utilization != nil {
+		t.Errorf("utilizations() cpu1 after reset = %+v, want nil", cpus[1].Utilization)
+	}
+
+	// the next measurement derives cpu1's utilization from its reset counters
+	cpu = sample("", 2400, 1200, 17500, 1200)
+	cpus = []Cpu{sample("cpu0", 1350, 650, 9000, 650), sample("cpu1", 60, 55, 480, 5)}
+	utilizations(&cpu, cpus)
+	if u := cpus[1].Utilization; u == nil || u.User != 10 || u.System != 10 || u.Idle != 80 {
+		t.Errorf("utilizations() cpu1 = %+v", u)
+	}
+}
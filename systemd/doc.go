@@ -0,0 +1,15 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+/*
+Package systemd measures the state and resource accounting of systemd units and observes their
+state changes for the "gomon" command.
+
+The systemd package defines the following command line flag:
+* -units: a regular expression to identify the names of units to measure and observe
+
+Units are queried with a single systemctl show per poll, shared by the measurements and observations.
+If systemctl fails, the observer warns, reports the systemd capability degraded, and retries with
+each poll. If systemctl is missing, or gomon runs in a container without systemd while observing
+the host, and on systems not booted with systemd, Darwin and Windows, no units are reported.
+*/
+package systemd
@@ -0,0 +1,25 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"github.com/zosmac/gocore"
+)
+
+var (
+	// flags defines the command line flags.
+	flags = struct {
+		units gocore.Regexp
+	}{}
+)
+
+// init initializes the command line flags.
+func init() {
+	flags.units.Set(`\.service$`)
+	gocore.Flags.Var(
+		&flags.units,
+		"units",
+		"[-units <expression>]",
+		"A regular `expression` matching names of systemd units to measure and observe (linux only)",
+	)
+}
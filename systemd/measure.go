@@ -0,0 +1,114 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/process"
+)
+
+type (
+	// unit contains the properties of a systemd unit.
+	unit map[string]string
+)
+
+var (
+	// properties lists the unit properties to query.
+	properties = []string{
+		"Id",
+		"Description",
+		"LoadState",
+		"ActiveState",
+		"SubState",
+		"Result",
+		"MainPID",
+		"NRestarts",
+		"MemoryCurrent",
+		"CPUUsageNSec",
+		"TasksCurrent",
+	}
+
+	// latest caches the units of the most recent query, so that Measure shares the observer's poll.
+	latest     map[string]unit
+	latestTime time.Time
+	latestLock sync.Mutex
+)
+
+// refresh queries the units and caches them.
+func refresh() (map[string]unit, error) {
+	us, err := units()
+	if err != nil {
+		return nil, err
+	}
+	latestLock.Lock()
+	latest, latestTime = us, time.Now()
+	latestLock.Unlock()
+	return us, nil
+}
+
+// cached returns the units of the most recent query unless it is older than the poll interval.
+func cached() (map[string]unit, error) {
+	latestLock.Lock()
+	us, at := latest, latestTime
+	latestLock.Unlock()
+	if time.Since(at) < poll {
+		return us, nil
+	}
+	return refresh()
+}
+
+// Measure captures systemd units' states and resource accounting.
+func Measure() []message.Content {
+	us, err := cached()
+	if err != nil {
+		gocore.Error("units", err).Err()
+		return nil
+	}
+
+	ms := make([]message.Content, 0, len(us))
+	for name, u := range us {
+		ms = append(ms, &Measurement{
+			Header:  message.Measurement(),
+			EventID: EventID{Unit: name},
+			Properties: Properties{
+				Description: u["Description"],
+				LoadState:   u["LoadState"],
+				ActiveState: u["ActiveState"],
+				SubState:    u["SubState"],
+				Result:      u["Result"],
+				MainProcess: u.mainProcess(),
+			},
+			Metrics: Metrics{
+				Restarts: u.value("NRestarts"),
+				Memory:   u.value("MemoryCurrent"),
+				Cpu:      time.Duration(u.value("CPUUsageNSec")),
+				Tasks:    u.value("TasksCurrent"),
+			},
+		})
+	}
+
+	return ms
+}
+
+// value converts a numeric property of a unit, for which systemd reports an unset value as "[not set]" or as the maximum uint64.
+func (u unit) value(key string) int {
+	v, err := strconv.ParseUint(u[key], 10, 64)
+	if err != nil || v > math.MaxInt64 {
+		return 0
+	}
+	return int(v)
+}
+
+// mainProcess identifies the main process of a unit.
+func (u unit) mainProcess() process.EventID {
+	if pid := u.value("MainPID"); pid > 0 {
+		return process.Identify(process.Pid(pid))
+	}
+	return process.EventID{}
+}
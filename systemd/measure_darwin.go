@@ -0,0 +1,8 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+// units reports no systemd units, which are not supported on darwin.
+func units() (map[string]unit, error) {
+	return nil, nil
+}
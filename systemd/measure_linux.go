@@ -0,0 +1,56 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+var (
+	// systemctl runs the systemctl command and returns its output, replaced by a fake for tests.
+	systemctl = func(args ...string) ([]byte, error) {
+		return exec.Command("systemctl", args...).Output()
+	}
+)
+
+// booted reports whether the system was booted with systemd, as determined by sd_booted(3).
+func booted() bool {
+	_, err := os.Stat(sysroot.Root("run", "systemd", "system"))
+	return err == nil
+}
+
+// units queries systemctl for the properties of the loaded units selected by the units flag.
+func units() (map[string]unit, error) {
+	if !booted() || flags.units.Regexp == nil {
+		return nil, nil
+	}
+
+	out, err := systemctl("show", "--no-pager", "--property="+strings.Join(properties, ","), "*")
+	if err != nil {
+		return nil, gocore.Error("systemctl show", err)
+	}
+
+	// the properties of each unit are separated by an empty line
+	us := map[string]unit{}
+	u := unit{}
+	for line := range strings.Lines(string(out) + "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if id := u["Id"]; id != "" && flags.units.MatchString(id) {
+				us[id] = u
+			}
+			u = unit{}
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			u[k] = v
+		}
+	}
+
+	return us, nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zosmac/gocore"
+)
+
+// fake replaces systemctl with canned output of systemctl show, counting its invocations.
+func fake(t *testing.T) *int {
+	t.Helper()
+
+	procfs := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(procfs, "1", "root", "run", "systemd", "system"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := gocore.Flags.FlagSet.Set("procfs", procfs); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	saved := systemctl
+	systemctl = func(args ...string) ([]byte, error) {
+		calls++
+		if args[0] != "show" || args[len(args)-1] != "*" {
+			t.Errorf("systemctl %v, want one show of all units", args)
+		}
+		return os.ReadFile(filepath.Join("testdata", "show"))
+	}
+	t.Cleanup(func() {
+		systemctl = saved
+		latest, latestTime = nil, time.Time{}
+		gocore.Flags.FlagSet.Set("procfs", "/proc")
+	})
+
+	return &calls
+}
+
+func TestUnits(t *testing.T) {
+	fake(t)
+
+	us, err := units()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := slices.Sorted(maps.Keys(us))
+	if want := []string{"cron.service", "nginx.service"}; !slices.Equal(names, want) {
+		t.Fatalf("units() = %v, want %v", names, want)
+	}
+	if u := us["nginx.service"]; u["ActiveState"] != "failed" || u["Result"] != "exit-code" {
+		t.Errorf("units() nginx.service = %v", u)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	calls := fake(t)
+
+	ms := Measure()
+	if len(ms) != 2 {
+		t.Fatalf("Measure() = %d measurements, want 2", len(ms))
+	}
+	for _, c := range ms {
+		m := c.(*Measurement)
+		switch m.Unit {
+		case "cron.service":
+			if m.Restarts != 2 || m.Memory != 1486848 || m.Cpu != 118273000 || m.Tasks != 1 || m.MainProcess.Pid != 612 {
+				t.Errorf("Measure() cron.service = %+v", m)
+			}
+		case "nginx.service":
+			if m.Restarts != 5 || m.Memory != 0 || m.Cpu != 0 || m.Tasks != 0 || m.MainProcess.Pid != 0 {
+				t.Errorf("Measure() nginx.service = %+v", m)
+			}
+		}
+	}
+
+	Measure()
+	if *calls != 1 {
+		t.Errorf("systemctl invoked %d times, want 1 per poll", *calls)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"time"
+
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/process"
+)
+
+func init() {
+	message.Define(&Measurement{})
+}
+
+type (
+	// EventID identifies the message.
+	EventID struct {
+		Unit string `json:"unit" gomon:"property"`
+	}
+
+	// Properties defines measurement properties.
+	Properties struct {
+		Description string          `json:"description" gomon:"property"`
+		LoadState   string          `json:"load_state" gomon:"enum,none"`
+		ActiveState string          `json:"active_state" gomon:"enum,none"`
+		SubState    string          `json:"sub_state" gomon:"enum,none"`
+		Result      string          `json:"result,omitempty" gomon:"enum,none"`
+		MainProcess process.EventID `json:"main_process" gomon:""`
+	}
+
+	// Metrics defines measurement metrics.
+	Metrics struct {
+		Restarts int           `json:"restarts" gomon:"counter,count"`
+		Memory   int           `json:"memory,omitempty" gomon:"gauge,B"`
+		Cpu      time.Duration `json:"cpu,omitempty" gomon:"counter,ns"`
+		Tasks    int           `json:"tasks,omitempty" gomon:"gauge,count"`
+	}
+
+	// Measurement defines the properties and metrics of a systemd unit measurement.
+	Measurement struct {
+		message.Header[message.MeasureEvent] `gomon:""`
+		EventID                              `json:"event_id" gomon:""`
+		Properties                           `gomon:""`
+		Metrics                              `gomon:""`
+	}
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Measurement) Events() []string {
+	return message.MeasureEvents.ValidValues()
+}
+
+// ID returns the identifier for a systemd unit message.
+func (m *Measurement) ID() string {
+	return m.EventID.Unit
+}
@@ -0,0 +1,57 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/message"
+	"github.com/zosmac/gomon/process"
+)
+
+func init() {
+	message.Define(&Observation{})
+}
+
+type (
+	// unitEvent type.
+	unitEvent string
+
+	// Observation defines the properties of a systemd unit state change message.
+	Observation struct {
+		message.Header[unitEvent] `gomon:""`
+		EventID                   `json:"event_id" gomon:""`
+		ActiveState               string          `json:"active_state" gomon:"property"`
+		SubState                  string          `json:"sub_state" gomon:"property"`
+		Result                    string          `json:"result,omitempty" gomon:"property"`
+		MainProcess               process.EventID `json:"main_process" gomon:""`
+		Message                   string          `json:"message" gomon:"property"`
+	}
+)
+
+const (
+	// message events.
+	unitStarted   unitEvent = "started"
+	unitStopped   unitEvent = "stopped"
+	unitFailed    unitEvent = "failed"
+	unitRestarted unitEvent = "restarted"
+)
+
+var (
+	// unitEvents valid event values for messages.
+	unitEvents = gocore.ValidValue[unitEvent]{}.Define(
+		unitStarted,
+		unitStopped,
+		unitFailed,
+		unitRestarted,
+	)
+)
+
+// Events returns the list of acceptable Event values for this message.
+func (*Observation) Events() []string {
+	return unitEvents.ValidValues()
+}
+
+// ID returns the identifier for a systemd unit state change message.
+func (obs *Observation) ID() string {
+	return obs.EventID.Unit
+}
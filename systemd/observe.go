@@ -0,0 +1,107 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zosmac/gocore"
+	"github.com/zosmac/gomon/capability"
+	"github.com/zosmac/gomon/message"
+)
+
+const (
+	// poll is the interval for querying units for state changes.
+	poll = 5 * time.Second
+)
+
+// Observer starts capture of systemd unit state change observations. If systemctl fails, the observer
+// reports the systemd capability degraded and retries with each poll.
+func Observer(ctx context.Context) error {
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		var prev map[string]unit
+		var primed, failing bool
+		for {
+			curr, err := refresh()
+			if err != nil {
+				if !failing {
+					failing = true
+					gocore.Error("units", err).Warn()
+					capability.Record(capability.Capability{
+						Name:   "systemd",
+						Mode:   capability.Degraded,
+						Detail: "systemctl failed, retrying",
+					})
+				}
+			} else {
+				if failing {
+					failing = false
+					gocore.Error("units", nil).Info()
+					capability.Record(capability.Capability{Name: "systemd", Mode: capability.Full})
+				}
+				if primed {
+					observe(prev, curr)
+				}
+				prev, primed = curr, true
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// observe reports the state changes of units between two queries.
+func observe(prev, curr map[string]unit) {
+	var obs []message.Content
+	for name, u := range curr {
+		if ev, ok := transition(prev[name], u); ok {
+			obs = append(obs, &Observation{
+				Header:      message.Observation(time.Now(), ev),
+				EventID:     EventID{Unit: name},
+				ActiveState: u["ActiveState"],
+				SubState:    u["SubState"],
+				Result:      u["Result"],
+				MainProcess: u.mainProcess(),
+				Message: fmt.Sprintf("%s %s/%s -> %s/%s",
+					name,
+					prev[name]["ActiveState"], prev[name]["SubState"],
+					u["ActiveState"], u["SubState"],
+				),
+			})
+		}
+	}
+
+	if len(obs) > 0 {
+		message.Observations(obs)
+	}
+}
+
+// transition determines the state change event of a unit since the previous query.
+func transition(prev, curr unit) (unitEvent, bool) {
+	was, is := prev["ActiveState"], curr["ActiveState"]
+	switch {
+	case prev != nil && curr.value("NRestarts") > prev.value("NRestarts"):
+		return unitRestarted, true
+	case is == "failed" && was != "failed":
+		return unitFailed, true
+	case is == "active" && was != "active":
+		return unitStarted, true
+	case is == "active" && prev.value("MainPID") > 0 && curr.value("MainPID") > 0 &&
+		curr.value("MainPID") != prev.value("MainPID"):
+		return unitRestarted, true
+	case is == "inactive" && (was == "active" || was == "deactivating"):
+		return unitStopped, true
+	}
+	return "", false
+}
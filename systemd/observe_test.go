@@ -0,0 +1,29 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"testing"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, curr unit
+		event      unitEvent
+	}{
+		{"started", unit{"ActiveState": "activating"}, unit{"ActiveState": "active"}, unitStarted},
+		{"failed", unit{"ActiveState": "active"}, unit{"ActiveState": "failed"}, unitFailed},
+		{"stopped", unit{"ActiveState": "deactivating"}, unit{"ActiveState": "inactive"}, unitStopped},
+		{"restarted", unit{"ActiveState": "active", "NRestarts": "1"}, unit{"ActiveState": "active", "NRestarts": "2"}, unitRestarted},
+		{"new main pid", unit{"ActiveState": "active", "MainPID": "10"}, unit{"ActiveState": "active", "MainPID": "11"}, unitRestarted},
+		{"unchanged", unit{"ActiveState": "active", "MainPID": "10"}, unit{"ActiveState": "active", "MainPID": "10"}, ""},
+	}
+
+	for _, tt := range tests {
+		ev, ok := transition(tt.prev, tt.curr)
+		if ev != tt.event || ok != (tt.event != "") {
+			t.Errorf("%s: transition() = %q, %t, want %q", tt.name, ev, ok, tt.event)
+		}
+	}
+}
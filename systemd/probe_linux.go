@@ -0,0 +1,31 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/zosmac/gomon/capability"
+	"github.com/zosmac/gomon/sysroot"
+)
+
+// Probe determines whether systemctl can query the units of the system's systemd.
+func Probe() []capability.Capability {
+	c := capability.Capability{Name: "systemd", Mode: capability.Full}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		c.Mode = capability.Disabled
+		c.Detail = "systemctl not found"
+	} else if !booted() {
+		c.Mode = capability.Disabled
+		c.Detail = "system not booted with systemd"
+	} else if _, err := os.Stat("/run/systemd/system"); sysroot.Relocated() && err != nil {
+		c.Mode = capability.Disabled
+		c.Detail = "systemctl cannot query the host's systemd from a container without it"
+	}
+	if c.Mode == capability.Disabled {
+		c.Measurements = []string{"systemd"}
+		c.Observations = []string{"systemd"}
+	}
+	return []capability.Capability{c}
+}
@@ -0,0 +1,12 @@
+// Copyright © 2021-2023 The Gomon Project.
+
+package systemd
+
+import (
+	"github.com/zosmac/gomon/capability"
+)
+
+// Probe determines the availability of systemd, which is not supported on windows.
+func Probe() []capability.Capability {
+	return nil
+}